@@ -0,0 +1,209 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"sync/atomic"
+)
+
+// `alias()` returns the list index `aMapIdx` resolves to.
+//
+// `aMapIdx` is the (canonical) list index to lookup.
+func (hl *THashList) alias(aMapIdx string) string {
+	// the mutex.Lock is done by the callers
+
+	if target, ok := hl.al[aMapIdx]; ok {
+		return target
+	}
+
+	return aMapIdx
+} // alias()
+
+// `aliasAdd()` makes `aAlias` an alias of `aTarget` returning
+// whether the alias table was changed.
+//
+// The IDs already stored for `aAlias` are merged into `aTarget`.
+//
+// `aAlias` is the (canonical) list index of the alias.
+//
+// `aTarget` is the (canonical) list index to use instead.
+func (hl *THashList) aliasAdd(aAlias, aTarget string) bool {
+	// the mutex.Lock is done by the callers
+
+	aTarget = hl.alias(aTarget)
+	if (aAlias == aTarget) || (aTarget == hl.al[aAlias]) {
+		// a cycle or nothing to do
+		return false
+	}
+	if nil == hl.al {
+		hl.al = make(map[string]string)
+	}
+	hl.al[aAlias] = aTarget
+	for alias, target := range hl.al {
+		// keep the table flat:
+		if target == aAlias {
+			hl.al[alias] = aTarget
+		}
+	}
+	hl.merge(aAlias, aTarget)
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalAlias, Tag: aAlias, Arg: aTarget})
+
+	return true
+} // aliasAdd()
+
+// `aliasRemove()` deletes `aAlias` from the alias table returning
+// whether it was removed.
+//
+// `aAlias` is the (canonical) list index of the alias.
+func (hl *THashList) aliasRemove(aAlias string) bool {
+	// the mutex.Lock is done by the callers
+
+	if _, ok := hl.al[aAlias]; !ok {
+		return false
+	}
+	delete(hl.al, aAlias)
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalAlias, Tag: aAlias})
+
+	return true
+} // aliasRemove()
+
+// `merge()` moves all IDs of `aFrom` to the list of `aTo` returning
+// whether the list was changed.
+//
+// `aFrom` is the (canonical) list index to remove.
+//
+// `aTo` is the (canonical) list index to extend.
+func (hl *THashList) merge(aFrom, aTo string) bool {
+	// the mutex.Lock is done by the callers
+
+	sl, ok := hl.hl[aFrom]
+	if !ok || (aFrom == aTo) {
+		return false
+	}
+	for _, id := range *sl {
+		hl.moveCount(aFrom, id, aTo, id)
+		hl.moveSeen(aFrom, id, aTo, id)
+	}
+	delete(hl.hl, aFrom)
+	for _, id := range *sl {
+		hl.hl.insert(aTo, id)
+		if nil != hl.ix {
+			hl.ix.discard(id, aFrom)
+			hl.ix.insert(id, aTo)
+		}
+	}
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalMerge, Tag: aFrom, Arg: aTo})
+
+	return true
+} // merge()
+
+// `tagIndex()` returns the canonical list index of `aTag` and whether
+// `aTag` starts with one of the list's sigils.
+//
+// `aTag` is the #hashtag/@mention to prepare.
+func (hl *THashList) tagIndex(aTag string) (string, bool) {
+	if (2 > len(aTag)) || !hl.isSigil(aTag[0]) {
+		return "", false
+	}
+
+	return mapIndex(aTag[0], aTag), true
+} // tagIndex()
+
+// AliasAdd makes `aAlias` an alias of `aTag`: from now on `aAlias`
+// is replaced by `aTag` whenever it's added or looked up (e.g. by
+// `IDparse()`, `HashList()` or `Query()`).
+//
+// The IDs already associated with `aAlias` are merged into the list
+// of `aTag`. If `aTag` is an alias itself the alias is resolved
+// first; aliases of `aAlias` become aliases of `aTag` as well.
+// The alias table is stored along with the list.
+//
+// `aAlias` is the #hashtag/@mention to replace (e.g. `#golang`).
+//
+// `aTag` is the #hashtag/@mention to use instead (e.g. `#go`).
+func (hl *THashList) AliasAdd(aAlias, aTag string) *THashList {
+	alias, ok1 := hl.tagIndex(aAlias)
+	target, ok2 := hl.tagIndex(aTag)
+	if !ok1 || !ok2 {
+		return hl
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	if hl.aliasAdd(alias, target) {
+		_ = hl.changed()
+	}
+
+	return hl
+} // AliasAdd()
+
+// AliasRemove deletes `aAlias` from the alias table.
+//
+// The IDs merged into the alias' target are not changed.
+//
+// `aAlias` is the #hashtag/@mention to use as a tag of its own again.
+func (hl *THashList) AliasRemove(aAlias string) *THashList {
+	alias, ok := hl.tagIndex(aAlias)
+	if !ok {
+		return hl
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	if hl.aliasRemove(alias) {
+		_ = hl.changed()
+	}
+
+	return hl
+} // AliasRemove()
+
+// Aliases returns a copy of the alias table mapping each alias
+// to the #hashtag/@mention used instead.
+func (hl *THashList) Aliases() map[string]string {
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	result := make(map[string]string, len(hl.al))
+	for alias, target := range hl.al {
+		result[alias] = target
+	}
+
+	return result
+} // Aliases()
+
+// MergeTags moves all IDs of `aFrom` to the list of `aTo` and
+// deletes `aFrom`.
+//
+// Unlike `AliasAdd()` this is a one-time operation, i.e. `aFrom`
+// can be used as a tag of its own afterwards. If `aTo` is an alias
+// the IDs are merged into the alias' target.
+//
+// `aFrom` is the #hashtag/@mention to fold into `aTo`.
+//
+// `aTo` is the #hashtag/@mention to receive the IDs of `aFrom`.
+func (hl *THashList) MergeTags(aFrom, aTo string) *THashList {
+	from, ok1 := hl.tagIndex(aFrom)
+	to, ok2 := hl.tagIndex(aTo)
+	if !ok1 || !ok2 {
+		return hl
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	if hl.merge(from, hl.alias(to)) {
+		_ = hl.changed()
+	}
+
+	return hl
+} // MergeTags()
+
+/* _EoF_ */
@@ -0,0 +1,195 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+)
+
+func TestTHashList_AliasAdd(t *testing.T) {
+	hl, _ := New("")
+	hl.IDparse("id_a", []byte("#golang is fun")).
+		IDparse("id_b", []byte("#Go rocks")).
+		AliasAdd("#golang", "#go").
+		AliasAdd("#go-lang", "#golang").
+		IDparse("id_c", []byte("#GoLang and #go-lang"))
+	tests := []struct {
+		name  string
+		aHash string
+		want  []string
+	}{
+		{" 1", "#go", []string{"id_a", "id_b", "id_c"}},
+		{" 2", "#golang", []string{"id_a", "id_b", "id_c"}},
+		{" 3", "go-lang", []string{"id_a", "id_b", "id_c"}},
+		{" 4", "#gopher", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.HashList(tt.aHash); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.HashList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	wa := map[string]string{"#golang": "#go", "#go-lang": "#go"}
+	if got := hl.Aliases(); !reflect.DeepEqual(got, wa) {
+		t.Errorf("THashList.Aliases() = %v, want %v", got, wa)
+	}
+	wc := []TCountItem{{3, "#go"}}
+	if got := hl.CountedList(); !reflect.DeepEqual(got, wc) {
+		t.Errorf("THashList.CountedList() = %v, want %v", got, wc)
+	}
+	if got, _ := hl.Query("#golang"); !reflect.DeepEqual(got, []string{"id_a", "id_b", "id_c"}) {
+		t.Errorf("THashList.Query() = %v, want %v", got, []string{"id_a", "id_b", "id_c"})
+	}
+	if got := hl.Matches([]byte("see #GoLang")); (1 != len(got)) || ("#go" != got[0].Index) {
+		t.Errorf("THashList.Matches() = %v, want index %q", got, "#go")
+	}
+
+	// cycles are ignored:
+	hl.AliasAdd("#go", "#go-lang")
+	if got := hl.Aliases(); !reflect.DeepEqual(got, wa) {
+		t.Errorf("THashList.AliasAdd() = %v, want %v", got, wa)
+	}
+	// a new tag becomes the target of all aliases:
+	hl.AliasAdd("#go", "#golang2")
+	wa2 := map[string]string{"#golang": "#golang2", "#go-lang": "#golang2", "#go": "#golang2"}
+	if got := hl.Aliases(); !reflect.DeepEqual(got, wa2) {
+		t.Errorf("THashList.AliasAdd() = %v, want %v", got, wa2)
+	}
+	if got := hl.HashLen("#golang2"); 3 != got {
+		t.Errorf("THashList.HashLen() = %v, want %v", got, 3)
+	}
+} // TestTHashList_AliasAdd()
+
+func TestTHashList_AliasRemove(t *testing.T) {
+	hl, _ := New("")
+	hl.IDparse("id_a", []byte("#golang is fun")).
+		IDparse("id_b", []byte("#Go rocks")).
+		AliasAdd("#golang", "#go").
+		AliasAdd("#go-lang", "#golang").
+		IDparse("id_c", []byte("#GoLang and #go-lang"))
+	hl.AliasRemove("#GOLANG").
+		AliasRemove("#unknown").
+		HashAdd("#golang", "id_d")
+	if got, want := hl.Aliases(), map[string]string{"#go-lang": "#go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.AliasRemove() = %v, want %v", got, want)
+	}
+	if got, want := hl.HashList("#golang"), []string{"id_d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.HashList() = %v, want %v", got, want)
+	}
+	if got, want := hl.HashList("#go"), []string{"id_a", "id_b", "id_c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.HashList() = %v, want %v", got, want)
+	}
+} // TestTHashList_AliasRemove()
+
+func TestTHashList_MergeTags(t *testing.T) {
+	hl, _ := New("")
+	hl.IDparse("id_a", []byte("#kubernets @bob")).
+		IDparse("id_b", []byte("#kubernetes #k8s")).
+		IDparse("id_c", []byte("#k8s"))
+	tests := []struct {
+		name  string
+		aFrom string
+		aTo   string
+		want  []TCountItem
+	}{
+		{" 1", "#kubernets", "#kubernetes", []TCountItem{{1, "@bob"}, {2, "#k8s"}, {2, "#kubernetes"}}},
+		{" 2", "#k8s", "#Kubernetes", []TCountItem{{1, "@bob"}, {3, "#kubernetes"}}},
+		{" 3", "#unknown", "#kubernetes", []TCountItem{{1, "@bob"}, {3, "#kubernetes"}}},
+		{" 4", "@bob", "invalid", []TCountItem{{1, "@bob"}, {3, "#kubernetes"}}},
+		{" 5", "@bob", "@bob", []TCountItem{{1, "@bob"}, {3, "#kubernetes"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.MergeTags(tt.aFrom, tt.aTo).CountedList(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.MergeTags() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// the merged tag can be used again:
+	if got, want := hl.HashAdd("#k8s", "id_d").HashLen("#k8s"), 1; got != want {
+		t.Errorf("THashList.HashLen() = %v, want %v", got, want)
+	}
+	if got, want := hl.IDlist("id_c"), []string{"#kubernetes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.IDlist() = %v, want %v", got, want)
+	}
+} // TestTHashList_MergeTags()
+
+func TestTHashList_aliasStorage(t *testing.T) {
+	fn1, fn2, fn3 := delDB("alias.db"), delDB("alias.txt"), delDB("alias.journal.db")
+	defer delDB(fn1)
+	defer delDB(fn2)
+	defer delDB(fn3)
+	tests := []struct {
+		name  string
+		fn    string
+		opts  []TOption
+		store bool
+	}{
+		{" 1", fn1, []TOption{WithFormat(FormatBinary)}, true},
+		{" 2", fn2, []TOption{WithFormat(FormatText)}, true},
+		{" 3", fn3, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl1, _ := New(tt.fn, tt.opts...)
+			hl1.IDparse("id_a", []byte("#golang is fun")).
+				IDparse("id_b", []byte("#Go rocks")).
+				AliasAdd("#golang", "#go").
+				AliasAdd("#go-lang", "#golang").
+				IDparse("id_c", []byte("#GoLang and #go-lang"))
+			hl1.MergeTags("#go", "#golang2")
+			if tt.store {
+				if _, err := hl1.Store(); nil != err {
+					t.Errorf("THashList.Store() error = %v", err)
+					return
+				}
+			} else if err := hl1.Flush(); nil != err {
+				t.Errorf("THashList.Flush() error = %v", err)
+				return
+			}
+			hl2, err := New(tt.fn, tt.opts...)
+			if nil != err {
+				t.Errorf("New() error = %v", err)
+				return
+			}
+			if got, want := hl2.String(), hl1.String(); got != want {
+				t.Errorf("New() = %v, want %v", got, want)
+			}
+			if got, want := hl2.HashAdd("#GoLang", "id_d").HashLen("#go"), 1; got != want {
+				t.Errorf("THashList.HashLen() = %v, want %v", got, want)
+			}
+		})
+	}
+} // TestTHashList_aliasStorage()
+
+func Test_loadTextAliasLines(t *testing.T) {
+	fn := delDB("alias.edited.txt")
+	defer delDB(fn)
+	// an ID line following an alias line (e.g. appended by hand):
+	text := "[#b]\nid_a\n[#a = #b]\nid_x\n"
+	if err := ioutil.WriteFile(fn, []byte(text), 0660); nil != err {
+		t.Errorf("ioutil.WriteFile() error = %v", err)
+		return
+	}
+	hl, err := New(fn, WithFormat(FormatText))
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got, want := hl.String(), "[#b]\nid_a\n[#a = #b]\n"; got != want {
+		t.Errorf("THashList.String() = %q, want %q", got, want)
+	}
+	hl.setData(&TStorageData{Tags: map[string][]string{"": {"id_y"}, "#c": {"id_c"}}})
+	if got, want := hl.String(), "[#c]\nid_c\n"; got != want {
+		t.Errorf("THashList.setData() = %q, want %q", got, want)
+	}
+} // Test_loadTextAliasLines()
+
+/* _EoF_ */
@@ -0,0 +1,225 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// TRejectFunc is called for each #hashtag/@mention rejected by
+	// the list's filter (see `WithFilter()`).
+	//
+	// `aTag` is the rejected tag's list index (i.e. its canonical form).
+	//
+	// `aID` is the ID the tag should have been added to.
+	TRejectFunc func(aTag, aID string)
+
+	// TFilter holds the rules which #hashtags/@mentions are accepted
+	// by a list.
+	//
+	// The rules are checked by `IDparse()`, `IDupdate()`, `HashAdd()`,
+	// `MentionAdd()` and `TagAdd()`; the tags already stored in the
+	// list are not affected. A tag is rejected if either its own name
+	// or the tag it is an alias of (see `AliasAdd()`) fails the rules.
+	//
+	// @see WithFilter()
+	TFilter struct {
+		// MinLength is the minimal number of characters (without
+		// the leading sigil) a tag must have; zero means no limit.
+		MinLength int
+
+		// MaxLength is the maximal number of characters (without
+		// the leading sigil) a tag may have; zero means no limit.
+		MaxLength int
+
+		// NoNumeric rejects tags without any letter (like `#1`,
+		// `#2024` or `#-`).
+		NoNumeric bool
+
+		// Blocklist lists the patterns of tags to reject (like
+		// `#nsfw*` or `@everyone`).
+		//
+		// In a pattern '*' matches any (possibly empty) sequence of
+		// characters and '?' matches a single character. Patterns
+		// starting with a sigil only match tags of that kind while
+		// other patterns (like `spam*`) match tags of all kinds.
+		Blocklist []string
+
+		// Allowlist lists the patterns of the only tags to accept
+		// (using the same syntax as `Blocklist`); an empty list
+		// accepts all tags not rejected otherwise.
+		Allowlist []string
+
+		// OnReject is called (if not `nil`) for each tag rejected.
+		//
+		// It's called while the list is locked, hence it must not
+		// call any of the list's methods.
+		OnReject TRejectFunc
+	}
+)
+
+// `globMatch()` reports whether `aText` matches `aPattern`
+// where '*' matches any sequence of characters and '?' matches
+// a single character.
+func globMatch(aPattern, aText string) bool {
+	var (
+		star     = -1 // position after the last '*' in `aPattern`
+		starText = 0  // position in `aText` matched by that '*'
+	)
+	p, t := 0, 0
+	for t < len(aText) {
+		if p < len(aPattern) {
+			switch aPattern[p] {
+			case '*':
+				p++
+				star, starText = p, t
+				continue
+			case '?':
+				_, size := utf8.DecodeRuneInString(aText[t:])
+				p, t = p+1, t+size
+				continue
+			default:
+				if aPattern[p] == aText[t] {
+					p, t = p+1, t+1
+					continue
+				}
+			}
+		}
+		if 0 > star {
+			return false
+		}
+		// let the last '*' match one more character:
+		_, size := utf8.DecodeRuneInString(aText[starText:])
+		starText += size
+		p, t = star, starText
+	}
+	for (p < len(aPattern)) && ('*' == aPattern[p]) {
+		p++
+	}
+
+	return p == len(aPattern)
+} // globMatch()
+
+// `matchAny()` reports whether the list index `aIndex` matches
+// one of `aPatterns`.
+func (f *TFilter) matchAny(aIndex string, aPatterns []string) bool {
+	for _, pattern := range aPatterns {
+		if (0 < len(pattern)) && (pattern[0] == aIndex[0]) && globMatch(pattern, aIndex) {
+			return true
+		}
+		if globMatch(pattern, aIndex[1:]) {
+			return true
+		}
+	}
+
+	return false
+} // matchAny()
+
+// `accepts()` reports whether the list index `aIndex` passes
+// all rules of the filter.
+func (f *TFilter) accepts(aIndex string) bool {
+	length := utf8.RuneCountInString(aIndex) - 1
+	if (0 < f.MinLength) && (length < f.MinLength) {
+		return false
+	}
+	if (0 < f.MaxLength) && (length > f.MaxLength) {
+		return false
+	}
+	if f.NoNumeric {
+		letter := false
+		for _, r := range aIndex[1:] {
+			if unicode.IsLetter(r) {
+				letter = true
+				break
+			}
+		}
+		if !letter {
+			return false
+		}
+	}
+	if f.matchAny(aIndex, f.Blocklist) {
+		return false
+	}
+	if (0 < len(f.Allowlist)) && !f.matchAny(aIndex, f.Allowlist) {
+		return false
+	}
+
+	return true
+} // accepts()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `accept()` reports whether the list's filter accepts `aMapIdx`
+// calling the filter's `OnReject` function if it doesn't.
+//
+// `aMapIdx` is the (canonical) list index to check.
+//
+// `aID` is the ID `aMapIdx` should be added to.
+func (hl *THashList) accept(aMapIdx, aID string) bool {
+	// the mutex.Lock is done by the callers
+
+	if !hl.rejects(aMapIdx) {
+		return true
+	}
+	if nil != hl.flt.OnReject {
+		hl.flt.OnReject(aMapIdx, aID)
+	}
+
+	return false
+} // accept()
+
+// `rejects()` reports whether the list's filter rejects `aMapIdx`
+// or the tag it is an alias of (see `AliasAdd()`).
+//
+// `aMapIdx` is the (canonical) list index to check.
+func (hl *THashList) rejects(aMapIdx string) bool {
+	// the mutex.Lock is done by the callers
+
+	if nil == hl.flt {
+		return false
+	}
+	if !hl.flt.accepts(aMapIdx) {
+		return true
+	}
+	target := hl.alias(aMapIdx)
+
+	return (target != aMapIdx) && !hl.flt.accepts(target)
+} // rejects()
+
+// WithFilter returns an option to restrict the #hashtags/@mentions
+// accepted by the list.
+//
+// The patterns of `aFilter` are converted to their canonical form
+// (see `TFilter`), so e.g. `#NSFW*` blocks `#nsfw` as well.
+//
+// `aFilter` holds the rules to apply.
+func WithFilter(aFilter TFilter) TOption {
+	return func(aList *THashList) {
+		filter := aFilter
+		filter.Blocklist = canonicalPatterns(aFilter.Blocklist)
+		filter.Allowlist = canonicalPatterns(aFilter.Allowlist)
+		aList.flt = &filter
+	}
+} // WithFilter()
+
+// `canonicalPatterns()` returns a copy of `aPatterns` converted to
+// their canonical form.
+func canonicalPatterns(aPatterns []string) (rList []string) {
+	for _, pattern := range aPatterns {
+		if 0 < len(pattern) {
+			rList = append(rList, canonical(pattern))
+		}
+	}
+
+	return
+} // canonicalPatterns()
+
+/* _EoF_ */
@@ -0,0 +1,143 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_globMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		aPattern string
+		aText    string
+		want     bool
+	}{
+		// TODO: Add test cases.
+		{" 1", "#nsfw", "#nsfw", true},
+		{" 2", "#nsfw", "#nsfw2", false},
+		{" 3", "#nsfw*", "#nsfw2", true},
+		{" 4", "#nsfw*", "#nsfw", true},
+		{" 5", "*spam*", "#getspamnow", true},
+		{" 6", "#?", "#ä", true},
+		{" 7", "#?", "#äb", false},
+		{" 8", "#a*b*c", "#axxbyyc", true},
+		{" 9", "#a*b*c", "#axxbyy", false},
+		{"10", "*", "", true},
+		{"11", "", "#x", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := globMatch(tt.aPattern, tt.aText); got != tt.want {
+				t.Errorf("globMatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_globMatch()
+
+func TestWithFilter(t *testing.T) {
+	long := "#" + strings.Repeat("x", 31)
+	tests := []struct {
+		name   string
+		filter TFilter
+		aText  string
+		want   []TCountItem
+	}{
+		// TODO: Add test cases.
+		{" 0", TFilter{}, "#1 #- @a #ok", []TCountItem{{1, "#-"}, {1, "#1"}, {1, "@a"}, {1, "#ok"}}},
+		{" 1", TFilter{MinLength: 2}, "#1 #- @a #ok", []TCountItem{{1, "#ok"}}},
+		{" 2", TFilter{MaxLength: 30}, "#ok " + long, []TCountItem{{1, "#ok"}}},
+		{" 3", TFilter{NoNumeric: true}, "#1 #- #2024 #2024年 @a", []TCountItem{{1, "#2024年"}, {1, "@a"}}},
+		{" 4", TFilter{Blocklist: []string{"#NSFW*", "@everyone"}}, "#nsfw #NSFW_pics @Everyone #everyone", []TCountItem{{1, "#everyone"}}},
+		{" 5", TFilter{Blocklist: []string{"spam*"}}, "#spam @spammer #nospam", []TCountItem{{1, "#nospam"}}},
+		{" 6", TFilter{Allowlist: []string{"#go*", "@*"}}, "#go #golang #rust @bob", []TCountItem{{1, "@bob"}, {1, "#go"}, {1, "#golang"}}},
+		{" 7", TFilter{Allowlist: []string{"#go*"}, Blocklist: []string{"#gossip"}}, "#go #gossip", []TCountItem{{1, "#go"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl, _ := New("", WithFilter(tt.filter))
+			if got := hl.IDparse("id_a", []byte(tt.aText)).CountedList(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestWithFilter()
+
+func TestTFilter_OnReject(t *testing.T) {
+	var rejected []string
+	hl, _ := New("", WithFilter(TFilter{
+		MinLength: 2,
+		Blocklist: []string{"@everyone", "#nsfw"},
+		OnReject: func(aTag, aID string) {
+			rejected = append(rejected, aTag+"|"+aID)
+		},
+	}))
+	hl.HashAdd("#NSFW", "id_a").
+		HashAdd("#fine", "id_a").
+		MentionAdd("everyone", "id_b").
+		MentionAdd("@x", "id_b").
+		TagAdd('#', "nsfw", "id_c").
+		IDparse("id_d", []byte("Hi @everyone, #1 #ok")).
+		IDupdate("id_e", []byte("#nsfw #ok"))
+	want := []string{"#nsfw|id_a", "@everyone|id_b", "@x|id_b", "#nsfw|id_c",
+		"@everyone|id_d", "#1|id_d", "#nsfw|id_e"}
+	if !reflect.DeepEqual(rejected, want) {
+		t.Errorf("TFilter.OnReject() = %v, want %v", rejected, want)
+	}
+	if got, want := hl.String(), "[#fine]\nid_a\n[#ok]\nid_d\nid_e\n"; got != want {
+		t.Errorf("THashList.String() = %q, want %q", got, want)
+	}
+} // TestTFilter_OnReject()
+
+func TestTFilter_Linkify(t *testing.T) {
+	rejected := 0
+	hl, _ := New("", WithFilter(TFilter{
+		Blocklist: []string{"#nsfw"},
+		OnReject: func(aTag, aID string) {
+			rejected++
+		},
+	}))
+	links := map[byte]TLinkFunc{
+		'#': LinkTemplate(`<a href="/tags/{name}">{tag}</a>`),
+	}
+	want := `#NSFW <a href="/tags/ok">#ok</a>`
+	if got := hl.Linkify([]byte("#NSFW #ok"), links); string(got) != want {
+		t.Errorf("THashList.Linkify() = %q, want %q", got, want)
+	}
+	if got := hl.Matches([]byte("#nsfw #ok")); (1 != len(got)) || ("#ok" != got[0].Index) {
+		t.Errorf("THashList.Matches() = %v, want index %q", got, "#ok")
+	}
+	if 0 != rejected {
+		t.Errorf("TFilter.OnReject() calls = %v, want %v", rejected, 0)
+	}
+} // TestTFilter_Linkify()
+
+func TestTFilter_alias(t *testing.T) {
+	var rejected []string
+	hl, _ := New("", WithFilter(TFilter{
+		Blocklist: []string{"#nsfw"},
+		OnReject: func(aTag, aID string) {
+			rejected = append(rejected, aTag+"|"+aID)
+		},
+	}))
+	hl.AliasAdd("#porn", "#nsfw").
+		IDparse("id_a", []byte("#porn #ok")).
+		HashAdd("#porn", "id_b")
+	if got, want := hl.IDlist("id_a"), []string{"#ok"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.IDlist() = %v, want %v", got, want)
+	}
+	if got, want := rejected, []string{"#porn|id_a", "#porn|id_b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("TFilter.OnReject() = %v, want %v", got, want)
+	}
+	if got := hl.Matches([]byte("#porn #ok")); (1 != len(got)) || ("#ok" != got[0].Index) {
+		t.Errorf("THashList.Matches() = %v, want index %q", got, "#ok")
+	}
+} // TestTFilter_alias()
+
+/* _EoF_ */
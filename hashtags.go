@@ -9,8 +9,6 @@ package hashtags
 //lint:file-ignore ST1017 - I prefer Yoda conditions
 
 import (
-	"bufio"
-	"encoding/gob"
 	"hash/crc32"
 	"os"
 	"regexp"
@@ -18,6 +16,11 @@ import (
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
 )
 
 type (
@@ -46,35 +49,68 @@ type (
 
 	// THashList is a list of `#hashtags` and `@mentions`
 	// pointing to sources (i.e. IDs).
+	//
+	// Errors writing modifications to the list's storage are
+	// reported by `Err()` and the handler set by `WithErrorHandler()`.
 	THashList struct {
-		fn      string        // the filename to use
-		hl      tHashMap      // the actual map list of sources/IDs
-		mtx     *sync.RWMutex // safeguard against concurrent accesses
-		µChange uint32        // internal change flag
-		µCC     tCountCache   // cache for `CountedList()`
+		fn       string            // the filename to use
+		al       map[string]string // aliases and the tags used instead
+		bak      bool              // keep a backup file if there's no `st`
+		clk      TClock            // clock for timestamps (nil: none)
+		flt      *TFilter          // optional rules of accepted tags
+		format   TFormat           // file format used if there's no `st`
+		hl       tHashMap          // the actual map list of sources/IDs
+		ix       tHashMap          // reverse index of IDs to their tags
+		jmax     int               // maximal size of the storage's journal
+		mtx      *sync.RWMutex     // safeguard against concurrent accesses
+		oc       tOccurrences      // counts of tags occurring repeatedly
+		occ      bool              // count occurrences per tag and ID
+		onErr    func(error)       // handler of persistence errors
+		policy   TPersistPolicy    // when to write modifications
+		sep      byte              // separator of hierarchical tags
+		sigils   []TSigil          // kinds of tags (nil: '#' and '@')
+		st       TStorage          // optional storage backend
+		tok      TTokenizer        // optional text analyser
+		ts       tSeenMap          // first/last seen times per tag and ID
+		µChange  uint32            // internal change flag
+		µCC      tCountCache       // cache for `CountedList()`
+		µErr     error             // last persistence error
+		µPending []TJournalEntry   // modifications not yet persisted
+		µTimer   *time.Timer       // timer of debounced persistence
 	}
+
+	// TOption is a function configuring a `THashList` instance
+	// when it's created by `New()`.
+	TOption func(aList *THashList)
 )
 
 var (
-	// UseBinaryStorage determines whether to use binary storage
-	// or not (i.e. plain text).
+	// UseBinaryStorage determines whether lists created by `New()`
+	// use binary storage or not (i.e. plain text) by default.
 	//
 	// Loading/storing binary data is about three times as fast with
 	// the `THashList` data than reading and parsing plain text data.
+	//
+	// This value is only consulted by `New()`; an existing list keeps
+	// its format. Use `WithFormat()` to choose the format per list.
 	UseBinaryStorage = true
 )
 
-// `add()` appends 'aID` to the list
+// `add()` inserts `aID` into the list keeping it sorted.
+//
+// The list is expected to be sorted already; it's a sorted set
+// which uses binary search to find the insertion point.
 //
 // `aID` the source ID to add to the list.
 func (sl *tSourceList) add(aID string) *tSourceList {
-	for _, id := range *sl {
-		if id == aID {
-			// already in list
-			return sl
-		}
+	idx := sort.SearchStrings(*sl, aID)
+	if (idx < len(*sl)) && ((*sl)[idx] == aID) {
+		// already in list
+		return sl
 	}
-	*sl = append(*sl, aID)
+	*sl = append(*sl, "")
+	copy((*sl)[idx+1:], (*sl)[idx:])
+	(*sl)[idx] = aID
 
 	return sl
 } // add()
@@ -88,12 +124,13 @@ func (sl *tSourceList) clear() *tSourceList {
 
 // `indexOf()` returns the list index of `aID`.
 //
+// The list is expected to be sorted.
+//
 // `aID` is the string to look up.
 func (sl *tSourceList) indexOf(aID string) int {
-	for result, id := range *sl {
-		if id == aID {
-			return result
-		}
+	idx := sort.SearchStrings(*sl, aID)
+	if (idx < len(*sl)) && ((*sl)[idx] == aID) {
+		return idx
 	}
 
 	return -1
@@ -107,25 +144,10 @@ func (sl *tSourceList) removeID(aID string) *tSourceList {
 	if 0 > idx {
 		return sl
 	}
-
-	slen := len(*sl) - 1
-	if 0 > slen {
-		// can't remove from empty list …
-		return sl
-	}
-
-	switch idx {
-	case 0:
-		if 0 == slen {
-			*sl = (*sl)[:0]
-		} else {
-			*sl = (*sl)[1:]
-		}
-	case slen:
-		*sl = (*sl)[:slen]
-	default:
-		*sl = append((*sl)[:idx], (*sl)[idx+1:]...)
-	}
+	last := len(*sl) - 1
+	copy((*sl)[idx:], (*sl)[idx+1:])
+	(*sl)[last] = "" // release the string for GC
+	*sl = (*sl)[:last]
 
 	return sl
 } // removeID()
@@ -139,21 +161,17 @@ func (sl *tSourceList) removeID(aID string) *tSourceList {
 //
 // `aNewID` is the replacement in this list.
 func (sl *tSourceList) renameID(aOldID, aNewID string) *tSourceList {
-	for idx, id := range *sl {
-		if id == aOldID {
-			(*sl)[idx] = aNewID
-			return sl.sort()
-		}
+	if 0 > sl.indexOf(aOldID) {
+		return sl
 	}
 
-	return sl
+	// if the new ID is already in the list `add()` is a no-op
+	return sl.removeID(aOldID).add(aNewID)
 } // renameID()
 
 // `sort()` returns the sorted list.
 func (sl *tSourceList) sort() *tSourceList {
-	sort.Slice(*sl, func(i, j int) bool {
-		return ((*sl)[i] < (*sl)[j]) // ascending
-	})
+	sort.Strings(*sl)
 
 	return sl
 } // sort()
@@ -169,12 +187,91 @@ func (sl *tSourceList) String() string {
 
 /* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
 
+// `discard()` removes `aValue` from the list of `aKey` returning
+// whether it was removed.
+//
+// If the list of `aKey` gets empty it's deleted.
+//
+// `aKey` identifies the list to use.
+//
+// `aValue` is the entry to remove from the list.
+func (hm tHashMap) discard(aKey, aValue string) bool {
+	sl, ok := hm[aKey]
+	if !ok || (0 > sl.indexOf(aValue)) {
+		return false
+	}
+	sl.removeID(aValue)
+	if 0 == len(*sl) {
+		delete(hm, aKey)
+	}
+
+	return true
+} // discard()
+
+// `insert()` adds `aValue` to the list of `aKey` returning
+// whether it was added (i.e. not already in the list).
+//
+// `aKey` identifies the list to use.
+//
+// `aValue` is the entry to add to the list.
+func (hm tHashMap) insert(aKey, aValue string) bool {
+	if sl, ok := hm[aKey]; ok {
+		if slen := len(*sl); slen == len(*sl.add(aValue)) {
+			// already in list
+			return false
+		}
+	} else {
+		sl := make(tSourceList, 1, 32)
+		sl[0] = aValue
+		hm[aKey] = &sl
+	}
+
+	return true
+} // insert()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `canonical()` returns the canonical form of `aTag` used as
+// list index.
+//
+// The text is normalised (NFKC) and case folded using the full
+// Unicode case folding so that e.g. `#Straße` and `#STRASSE` or
+// the composed and decomposed forms of `#café` are the same tag.
+//
+// `aTag` is the #hashtag/@mention to canonicalise.
+func canonical(aTag string) string {
+	for idx := 0; idx < len(aTag); idx++ {
+		if utf8.RuneSelf <= aTag[idx] {
+			// `cases.Caser` is stateful, hence a new one for each call
+			return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(aTag)))
+		}
+	}
+
+	// fast path for plain ASCII
+	return strings.ToLower(aTag)
+} // canonical()
+
+// `mapIndex()` returns the (canonical) list index of `aMapIdx`
+// starting with `aDelim`.
+//
+// `aDelim` is the start character of words to use (i.e. a sigil like '@' or '#').
+//
+// `aMapIdx` is the #hashtag/@mention to prepare.
+func mapIndex(aDelim byte, aMapIdx string) string {
+	aMapIdx = canonical(aMapIdx) // prepare for case-insensitive search
+	if aMapIdx[0] != aDelim {
+		aMapIdx = string(aDelim) + aMapIdx
+	}
+
+	return aMapIdx
+} // mapIndex()
+
 // `add()` appends `aID` to the list associated with `aMapIdx`.
 //
 // If either `aMapIdx` or `aID` are empty strings they are silently
 // ignored (i.e. this method does nothing).
 //
-// `aDelim` is the start character of words to use (i.e. either '@' or '#').
+// `aDelim` is the start character of words to use (i.e. a sigil like '@' or '#').
 //
 // `aMapIdx` is the list index to lookup.
 //
@@ -185,12 +282,17 @@ func (hl *THashList) add(aDelim byte, aMapIdx, aID string) *THashList {
 	if (0 == len(aMapIdx)) || (0 == len(aID)) {
 		return hl
 	}
-	aMapIdx = strings.ToLower(aMapIdx) // prepare for case-insensitive search
-	if aMapIdx[0] != aDelim {
-		aMapIdx = string(aDelim) + aMapIdx
+
+	mapIdx := mapIndex(aDelim, aMapIdx)
+	if !hl.accept(mapIdx, aID) {
+		return hl
 	}
 
-	return hl.add0(aMapIdx, aID)
+	mapIdx = hl.alias(mapIdx)
+	hl.add0(mapIdx, aID)
+	hl.touch(aID, mapIdx)
+
+	return hl
 } // add()
 
 // `add0()` appends `aID` to the list associated with `aMapIdx`.
@@ -201,14 +303,14 @@ func (hl *THashList) add(aDelim byte, aMapIdx, aID string) *THashList {
 func (hl *THashList) add0(aMapIdx, aID string) *THashList {
 	// the mutex.Lock is done by the callers
 
-	if sl, ok := hl.hl[aMapIdx]; ok {
-		sl.add(aID).sort()
-	} else {
-		sl := make(tSourceList, 1, 32)
-		sl[0] = aID
-		hl.hl[aMapIdx] = &sl
+	if !hl.hl.insert(aMapIdx, aID) {
+		return hl
+	}
+	if nil != hl.ix {
+		hl.ix.insert(aID, aMapIdx)
 	}
 	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalAdd, Tag: aMapIdx, ID: aID})
 
 	return hl
 } // add0()
@@ -244,13 +346,21 @@ func (hl *THashList) clear() *THashList {
 		sl.clear()
 		delete(hl.hl, mapIdx)
 	}
+	if nil != hl.ix {
+		hl.ix = make(tHashMap)
+	}
+	hl.oc, hl.ts = nil, nil
 	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalClear})
 
 	return hl
 } // clear()
 
 // Clear empties the internal data structures:
 // all `#hashtags` and `@mentions` are deleted.
+//
+// The list's storage is not changed until the list is modified
+// or stored the next time.
 func (hl *THashList) Clear() *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
@@ -260,6 +370,9 @@ func (hl *THashList) Clear() *THashList {
 
 // CountedList returns a list of #hashtags/@mentions with
 // their respective count of associated IDs.
+//
+// If the list counts occurrences (see `WithOccurrences()`) the
+// count is the total number of occurrences in all IDs.
 func (hl *THashList) CountedList() []TCountItem {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
@@ -272,7 +385,14 @@ func (hl *THashList) CountedList() []TCountItem {
 	hl.µCC.µCRC = hl.checksum()
 	result := make(tCountList, 0, len(hl.hl))
 	for mapIdx, sl := range hl.hl {
-		result = append(result, TCountItem{len(*sl), mapIdx})
+		count := len(*sl)
+		if hl.occ {
+			for _, n := range hl.oc[mapIdx] {
+				// the IDs counted already once:
+				count += n - 1
+			}
+		}
+		result = append(result, TCountItem{count, mapIdx})
 	}
 	if 0 < len(result) {
 		sort.Slice(result, func(i, j int) bool {
@@ -285,6 +405,38 @@ func (hl *THashList) CountedList() []TCountItem {
 	return result
 } // CountedList()
 
+// `deleteTag()` removes the list of `aMapIdx` altogether.
+//
+// `aMapIdx` is the (canonical) list index to delete.
+func (hl *THashList) deleteTag(aMapIdx string) *THashList {
+	// the mutex.Lock is done by the callers
+
+	sl, ok := hl.hl[aMapIdx]
+	if !ok {
+		return hl
+	}
+	delete(hl.hl, aMapIdx)
+	delete(hl.oc, aMapIdx)
+	delete(hl.ts, aMapIdx)
+	if nil != hl.ix {
+		for _, id := range *sl {
+			hl.ix.discard(id, aMapIdx)
+		}
+	}
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalDelete, Tag: aMapIdx})
+
+	return hl
+} // deleteTag()
+
+// Format returns the file format used when storing this list.
+func (hl *THashList) Format() TFormat {
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	return hl.format
+} // Format()
+
 // Filename returns the configured filename for reading/storing this list.
 func (hl *THashList) Filename() string {
 	hl.mtx.RLock()
@@ -305,7 +457,9 @@ func (hl *THashList) HashAdd(aHash, aID string) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	return hl.add('#', aHash, aID)
+	_ = hl.add('#', aHash, aID).changed()
+
+	return hl
 } // HashAdd()
 
 // HashLen returns the number of IDs stored for `aHash`.
@@ -336,15 +490,8 @@ func (hl *THashList) IDlist(aID string) (rList []string) {
 	hl.mtx.RLock()
 	defer hl.mtx.RUnlock()
 
-	for mapIdx, sl := range hl.hl {
-		if 0 <= sl.indexOf(aID) {
-			rList = append(rList, mapIdx)
-		}
-	}
-	if 0 < len(rList) {
-		sort.Slice(rList, func(i, j int) bool {
-			return (rList[i] < rList[j]) // ascending
-		})
+	if tags, ok := hl.index()[aID]; ok {
+		rList = append(rList, (*tags)...)
 	}
 
 	return
@@ -360,14 +507,9 @@ func (hl *THashList) IDparse(aID string, aText []byte) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	oldCRC := hl.checksum()
-	defer func() {
-		if oldCRC != atomic.LoadUint32(&hl.µChange) {
-			_, _ = hl.store()
-		}
-	}()
+	_ = hl.parseID(aID, aText).changed()
 
-	return hl.parseID(aID, aText)
+	return hl
 } // IDparse()
 
 // IDremove deletes all @hashtags/@mentions associated with `aID`.
@@ -377,7 +519,9 @@ func (hl *THashList) IDremove(aID string) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	return hl.removeID(aID)
+	_ = hl.removeID(aID).changed()
+
+	return hl
 } // IDremove()
 
 // IDrename replaces all occurrences of `aOldID` by `aNewID`.
@@ -392,11 +536,7 @@ func (hl *THashList) IDrename(aOldID, aNewID string) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	for _, sl := range hl.hl {
-		sl.renameID(aOldID, aNewID)
-	}
-	atomic.StoreUint32(&hl.µChange, 0)
-	_, _ = hl.store()
+	_ = hl.renameID(aOldID, aNewID).changed()
 
 	return hl
 } // IDrename()
@@ -411,19 +551,45 @@ func (hl *THashList) IDupdate(aID string, aText []byte) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	oldCRC := hl.checksum()
-	defer func() {
-		if oldCRC != atomic.LoadUint32(&hl.µChange) {
-			_, _ = hl.store()
-		}
-	}()
+	_ = hl.updateID(aID, aText).changed()
 
-	return hl.updateID(aID, aText)
+	return hl
 } // IDupdate()
 
+// `index()` returns the reverse index mapping each ID to its
+// #hashtags/@mentions.
+//
+// The index is built when the list is loaded (see `setData()`) and
+// then kept up to date by all methods modifying the list, so that
+// reading it needs only a read lock. For lists without an index
+// (i.e. not created by `New()`) a temporary one is returned.
+func (hl *THashList) index() tHashMap {
+	// the mutex.Lock is done by the callers
+
+	if nil != hl.ix {
+		return hl.ix
+	}
+
+	return hl.reverse()
+} // index()
+
+// `reverse()` returns a new reverse index of the list's contents.
+func (hl *THashList) reverse() tHashMap {
+	// the mutex.Lock is done by the callers
+
+	result := make(tHashMap, len(hl.hl))
+	for mapIdx, sl := range hl.hl {
+		for _, id := range *sl {
+			result.insert(id, mapIdx)
+		}
+	}
+
+	return result
+} // reverse()
+
 // `idxLen()` returns the number of IDs stored for `aMapIdx`.
 //
-// `aDelim` is the first character of words to use (i.e. either '@' or '#').
+// `aDelim` is the first character of words to use (i.e. a sigil like '@' or '#').
 //
 // `aMapIdx` identifies the ID list to lookup.
 func (hl *THashList) idxLen(aDelim byte, aMapIdx string) int {
@@ -433,11 +599,7 @@ func (hl *THashList) idxLen(aDelim byte, aMapIdx string) int {
 	if 0 == len(aMapIdx) {
 		return -1
 	}
-	aMapIdx = strings.ToLower(aMapIdx)
-	if aMapIdx[0] != aDelim {
-		aMapIdx = string(aDelim) + aMapIdx
-	}
-	if sl, ok := (hl.hl)[aMapIdx]; ok {
+	if sl, ok := hl.hl[hl.alias(mapIndex(aDelim, aMapIdx))]; ok {
 		return len(*sl)
 	}
 
@@ -468,7 +630,7 @@ func (hl *THashList) LenTotal() int {
 
 // `list()` returns a list of IDs associated with `aMapIdx`.
 //
-// `aDelim` is the start of words to search (i.e. either '@' or '#').
+// `aDelim` is the start of words to search (i.e. a sigil like '@' or '#').
 //
 // `aMapIdx` identifies the sources list to lookup.
 func (hl *THashList) list(aDelim byte, aMapIdx string) (rList []string) {
@@ -478,20 +640,16 @@ func (hl *THashList) list(aDelim byte, aMapIdx string) (rList []string) {
 	if 0 == len(aMapIdx) {
 		return
 	}
-	aMapIdx = strings.ToLower(aMapIdx)
-	if aMapIdx[0] != aDelim {
-		aMapIdx = string(aDelim) + aMapIdx
-	}
-	if sl, ok := hl.hl[aMapIdx]; ok {
-		sl.sort()
-		rList = []string(*sl)
+	if sl, ok := hl.hl[hl.alias(mapIndex(aDelim, aMapIdx))]; ok {
+		// a copy, so the caller can't modify the list:
+		rList = append([]string(nil), (*sl)...)
 	}
 
 	return
 } // list()
 
-// Load reads the configured filen returning the data structure
-// read from the file and a possible error condition.
+// Load reads the configured storage returning the data structure
+// read from it and a possible error condition.
 //
 // If the hash file doesn't exist that is not considered an error.
 // If there is an error, it will be of type `*PathError`.
@@ -499,69 +657,18 @@ func (hl *THashList) Load() (*THashList, error) {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	file, err := os.OpenFile(hl.fn, os.O_RDONLY, 0)
+	st := hl.storage()
+	if nil == st {
+		return hl, nil
+	}
+	data, err := st.Load()
 	if nil != err {
-		if os.IsNotExist(err) {
-			return hl, nil
-		}
 		return hl, err
 	}
-	defer file.Close()
-	if UseBinaryStorage {
-		return hl.loadBinary(file)
-	}
 
-	return hl.loadText(file)
+	return hl.setData(data).replay(data.Journal), nil
 } // Load()
 
-// `loadBinary()` reads a file written by `store()` returning
-// the modified list and a possible error.
-func (hl *THashList) loadBinary(aFile *os.File) (*THashList, error) {
-	// The mutex.Lock is done by the caller
-
-	var decodedMap tHashMap
-	decoder := gob.NewDecoder(aFile)
-	if err := decoder.Decode(&decodedMap); err != nil {
-		return hl, err
-	}
-	hl.hl = decodedMap
-
-	return hl, nil
-} // loadBinary()
-
-// `loadText()` parses a file written by `store()` returning
-// the modified list and a possible error.
-//
-// This method reads one line of the file at a time.
-func (hl *THashList) loadText(aFile *os.File) (*THashList, error) {
-	// The mutex.Lock is done by the caller
-
-	var (
-		mapIdx string
-		rRead  int
-	)
-	scanner := bufio.NewScanner(aFile)
-	hl.clear()
-	for lineRead := scanner.Scan(); lineRead; lineRead = scanner.Scan() {
-		line := scanner.Text()
-		rRead += len(line) + 1 // add trailing LF
-
-		line = strings.TrimSpace(line)
-		if 0 == len(line) {
-			continue
-		}
-
-		if matches := hashHeadRE.FindStringSubmatch(line); nil != matches {
-			mapIdx = strings.ToLower(strings.TrimSpace(matches[1]))
-		} else {
-			hl.add0(mapIdx, line)
-		}
-	}
-	atomic.StoreUint32(&hl.µChange, 0)
-
-	return hl, scanner.Err()
-} // loadText()
-
 // MentionAdd appends `aID` to the list of `aMention`.
 //
 // If either `aMention` or `aID` are empty strings they are
@@ -574,7 +681,9 @@ func (hl *THashList) MentionAdd(aMention, aID string) *THashList {
 	hl.mtx.Lock()
 	defer hl.mtx.Unlock()
 
-	return hl.add('@', aMention, aID)
+	_ = hl.add('@', aMention, aID).changed()
+
+	return hl
 } // MentionAdd()
 
 // MentionLen returns the number of IDs stored for `aMention`.
@@ -601,14 +710,11 @@ func (hl *THashList) MentionRemove(aMention, aID string) *THashList {
 } // MentionRemove()
 
 var (
-	// match: [#Hashtag|@mention]
-	hashHeadRE = regexp.MustCompile(`^\[\s*([#@][^\]]*?)\s*\]$`)
+	// match: [#Hashtag|@mention|$cashtag…] (see `WithSigil()`)
+	hashHeadRE = regexp.MustCompile(`^\[\s*([#$%+@^~][^\]]*?)\s*\]$`)
 
-	// RegEx to identify a numeric HTML entity.
-	entityRE = regexp.MustCompile(`(#[0-9]+;)`)
-
-	// match: #hashtag|@mention
-	hashMentionRE = regexp.MustCompile(`(?i)\b?([@#][§\wÄÖÜß-]+)(.?|$)`)
+	// match: [#alias = #hashtag] (see `AliasAdd()`)
+	aliasHeadRE = regexp.MustCompile(`^\[\s*([#$%+@^~][^\]=\s]*)\s*=\s*([#$%+@^~][^\]=\s]*)\s*\]$`)
 )
 
 // `parseID()` checks whether `aText` contains strings starting
@@ -620,41 +726,19 @@ var (
 func (hl *THashList) parseID(aID string, aText []byte) *THashList {
 	// The mutex.Lock is done by the caller
 
-	matches := hashMentionRE.FindAllSubmatch(aText, -1)
-	if (nil == matches) || (0 >= len(matches)) {
-		return hl
-	}
-	for _, sub := range matches {
-		if 0 < len(sub[1]) {
-			hash := string(sub[1])
-			// '_' can be both, part of the hashtag and italic markup
-			// so we must remove it if it's at the end:
-			if '_' == hash[len(hash)-1] {
-				hash = hash[:len(hash)-1]
-			}
-			if '#' == hash[0] {
-				if 0 < len(sub[2]) {
-					if '"' == sub[2][0] {
-						// double quote following a possible hashtag: most
-						// probably an URL#fragment, hence leave it as is
-						continue
-					}
-					if (';' == sub[2][0]) && entityRE.MatchString(hash+";") {
-						// leave HTML entities as is
-						continue
-					}
-				}
-			}
-			hl.add(hash[0], hash, aID)
-		}
+	tags := hl.parseTags(aID, aText)
+	for _, mapIdx := range tags {
+		hl.add0(mapIdx, aID)
 	}
+	hl.setCounts(aID, tags)
+	hl.touch(aID, tags...)
 
 	return hl
 } // parseID()
 
 // `remove()` deletes `aID` from the list of `aMapIdx`.
 //
-// `aDelim` is the start character of words to use (i.e. either '@' or '#').
+// `aDelim` is the start character of words to use (i.e. a sigil like '@' or '#').
 //
 // `aMapIdx` identifies the sources list to lookup.
 //
@@ -666,20 +750,32 @@ func (hl *THashList) remove(aDelim byte, aMapIdx, aID string) *THashList {
 	if (0 == len(aMapIdx)) || (0 == len(aID)) {
 		return hl
 	}
-	aMapIdx = strings.ToLower(aMapIdx)
-	if aMapIdx[0] != aDelim {
-		aMapIdx = string(aDelim) + aMapIdx
+	_ = hl.remove0(hl.alias(mapIndex(aDelim, aMapIdx)), aID).changed()
+
+	return hl
+} // remove()
+
+// `remove0()` deletes `aID` from the list of `aMapIdx`.
+//
+// `aMapIdx` identifies the sources list to lookup.
+//
+// `aID` is the source to remove from the list.
+func (hl *THashList) remove0(aMapIdx, aID string) *THashList {
+	// The mutex.Lock is done by the callers
+
+	if !hl.hl.discard(aMapIdx, aID) {
+		return hl
 	}
-	if sl, ok := hl.hl[aMapIdx]; ok {
-		sl.removeID(aID)
-		if 0 == len(*hl.hl[aMapIdx]) {
-			delete(hl.hl, aMapIdx)
-		}
-		atomic.StoreUint32(&hl.µChange, 0)
+	hl.setCount0(aMapIdx, aID, 1)
+	hl.setSeen0(aMapIdx, aID, TSeen{})
+	if nil != hl.ix {
+		hl.ix.discard(aID, aMapIdx)
 	}
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalRemove, Tag: aMapIdx, ID: aID})
 
 	return hl
-} // remove()
+} // remove0()
 
 // `removeID()` deletes all @hashtags/@mentions associated with `aID`.
 //
@@ -687,25 +783,80 @@ func (hl *THashList) remove(aDelim byte, aMapIdx, aID string) *THashList {
 func (hl *THashList) removeID(aID string) *THashList {
 	// The mutex.Lock is done by the callers
 
-	oldCRC := hl.checksum()
-	defer func() {
-		if oldCRC != atomic.LoadUint32(&hl.µChange) {
-			_, _ = hl.store()
+	if tags, ok := hl.index()[aID]; ok {
+		for _, mapIdx := range append(tSourceList(nil), (*tags)...) {
+			hl.remove0(mapIdx, aID)
 		}
-	}()
+	}
 
-	for mapIdx, sl := range hl.hl {
-		sl.removeID(aID)
-		if 0 == len(*hl.hl[mapIdx]) {
-			delete(hl.hl, mapIdx)
-		}
+	return hl
+} // removeID()
+
+// `renameID()` replaces all occurrences of `aOldID` by `aNewID`.
+//
+// `aOldID` is to be replaced in all lists.
+//
+// `aNewID` is the replacement in all lists.
+func (hl *THashList) renameID(aOldID, aNewID string) *THashList {
+	// The mutex.Lock is done by the callers
+
+	if (0 == len(aNewID)) || (aOldID == aNewID) {
+		return hl
+	}
+	ix := hl.index()
+	tags, ok := ix[aOldID]
+	if !ok {
+		return hl
+	}
+	delete(ix, aOldID)
+	for _, mapIdx := range *tags {
+		hl.moveCount(mapIdx, aOldID, mapIdx, aNewID)
+		hl.moveSeen(mapIdx, aOldID, mapIdx, aNewID)
+		hl.hl[mapIdx].renameID(aOldID, aNewID)
+		ix.insert(aNewID, mapIdx)
 	}
 	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalRename, ID: aOldID, Arg: aNewID})
 
 	return hl
-} // IDremove()
+} // renameID()
+
+// `renameTag()` moves the list of `aOldIdx` to `aNewIdx` merging it
+// with an existing list; aliases of `aOldIdx` become aliases of
+// `aNewIdx`. If `aNewIdx` is an alias of `aOldIdx` that alias is
+// removed first.
+//
+// `aOldIdx` is the (canonical) list index to rename.
+//
+// `aNewIdx` is the (canonical) list index to use instead.
+func (hl *THashList) renameTag(aOldIdx, aNewIdx string) *THashList {
+	// the mutex.Lock is done by the callers
+
+	aOldIdx = hl.alias(aOldIdx)
+	if target := hl.alias(aNewIdx); (target == aOldIdx) && (aNewIdx != aOldIdx) {
+		// the new name currently stands for the old tag:
+		hl.aliasRemove(aNewIdx)
+	} else {
+		aNewIdx = target
+	}
+	if aOldIdx == aNewIdx {
+		return hl
+	}
+	hl.merge(aOldIdx, aNewIdx)
+	for alias, target := range hl.al {
+		if target == aOldIdx {
+			hl.al[alias] = aNewIdx
+			atomic.StoreUint32(&hl.µChange, 0)
+			hl.journal(TJournalEntry{Op: JournalAlias, Tag: alias, Arg: aNewIdx})
+		}
+	}
+
+	return hl
+} // renameTag()
 
 // SetFilename sets `aFilename` to use by this list.
+//
+// The filename is ignored if a storage was configured by `WithStorage()`.
 func (hl *THashList) SetFilename(aFilename string) *THashList {
 	hl.mtx.RLock()
 	defer hl.mtx.RUnlock()
@@ -715,39 +866,188 @@ func (hl *THashList) SetFilename(aFilename string) *THashList {
 	return hl
 } // SetFilename()
 
-// `store()` writes the whole list to the configured file
+// `setData()` replaces the list's contents by `aData`.
+//
+// `aData` is the data read from the list's storage.
+func (hl *THashList) setData(aData *TStorageData) *THashList {
+	// the mutex.Lock is done by the callers
+
+	hl.clear()
+	hl.al = nil
+	for alias, target := range aData.Aliases {
+		if alias, target = canonical(alias), canonical(target); alias != target {
+			if nil == hl.al {
+				hl.al = make(map[string]string, len(aData.Aliases))
+			}
+			hl.al[alias] = target
+		}
+	}
+	for mapIdx, ids := range aData.Tags {
+		if (0 == len(ids)) || (0 == len(mapIdx)) {
+			continue
+		}
+		// Data written by earlier versions might use keys which
+		// collapse to the same canonical form, hence we merge them:
+		mapIdx = hl.alias(canonical(mapIdx))
+		sl := make(tSourceList, 0, len(ids))
+		if old, ok := hl.hl[mapIdx]; ok {
+			sl = append(sl, (*old)...)
+		}
+		sl = append(sl, ids...)
+		sl.sort()
+		// remove duplicates possibly written by an external tool:
+		last := 0
+		for idx := 1; idx < len(sl); idx++ {
+			if sl[idx] != sl[last] {
+				last++
+				sl[last] = sl[idx]
+			}
+		}
+		sl = sl[:last+1]
+		hl.hl[mapIdx] = &sl
+	}
+	if hl.occ {
+		// The counts of merged keys are summed up; an ID without
+		// a count occurs once in each key listing it:
+		sums := make(map[string]map[string]int, len(aData.Counts))
+		for mapIdx, ids := range aData.Tags {
+			if (0 == len(ids)) || (0 == len(mapIdx)) {
+				continue
+			}
+			counts := aData.Counts[mapIdx]
+			mapIdx = hl.alias(canonical(mapIdx))
+			sum, ok := sums[mapIdx]
+			if !ok {
+				sum = make(map[string]int, len(ids))
+				sums[mapIdx] = sum
+			}
+			done := make(map[string]bool, len(ids))
+			for _, id := range ids {
+				if done[id] {
+					continue
+				}
+				done[id] = true
+				if n, ok := counts[id]; ok {
+					sum[id] += n
+				} else {
+					sum[id]++
+				}
+			}
+		}
+		for mapIdx, sum := range sums {
+			for id, n := range sum {
+				hl.setCount0(mapIdx, id, n)
+			}
+		}
+	}
+	if nil != hl.clk {
+		for mapIdx, ids := range aData.Seen {
+			mapIdx = hl.alias(canonical(mapIdx))
+			for id, seen := range ids {
+				if sl, ok := hl.hl[mapIdx]; ok && (0 <= sl.indexOf(id)) {
+					hl.setSeen0(mapIdx, id, hl.ts[mapIdx][id].merged(seen))
+				}
+			}
+		}
+	}
+	hl.ix = hl.reverse()
+	atomic.StoreUint32(&hl.µChange, 0)
+	// the data is already part of the storage:
+	hl.µPending = nil
+
+	return hl
+} // setData()
+
+// `storage()` returns the list's storage backend.
+//
+// If no storage was configured by `WithStorage()` a file storage
+// using the list's filename is returned; if there's no filename
+// either the result is `nil`.
+func (hl *THashList) storage() TStorage {
+	if nil != hl.st {
+		return hl.st
+	}
+	if 0 == len(hl.fn) {
+		return nil
+	}
+	fs := tFileStorage{fn: hl.fn, bak: hl.bak}
+	if FormatText == hl.format {
+		return &tTextStorage{fs}
+	}
+
+	return &tBinaryStorage{fs}
+} // storage()
+
+// `store()` writes the whole list to the configured storage
 // returning the number of bytes written and a possible error.
 //
 // If there is an error, it will be of type `*PathError`.
 func (hl *THashList) store() (int, error) {
 	// the mutex.Lock is done by the callers
 
-	file, err := os.OpenFile(hl.fn, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0660) //#nosec G302
-	if nil != err {
-		return 0, err
+	st := hl.storage()
+	if nil == st {
+		return 0, &os.PathError{Op: "open", Path: hl.fn, Err: os.ErrNotExist}
+	}
+	written, err := st.Save(hl.storageData())
+	if nil == err {
+		hl.µErr, hl.µPending = nil, nil
 	}
-	defer file.Close()
 
-	if UseBinaryStorage {
-		encoder := gob.NewEncoder(file)
-		if err = encoder.Encode(hl.hl); nil != err {
-			return 0, err
-		}
-		size, err := file.Seek(0, os.SEEK_END)
+	return written, err
+} // store()
+
+// `storageData()` returns the list's contents to be written
+// by the list's storage.
+func (hl *THashList) storageData() *TStorageData {
+	// the mutex.Lock is done by the callers
 
-		return int(size), err
+	result := &TStorageData{
+		Tags: make(map[string][]string, len(hl.hl)),
+	}
+	for mapIdx, sl := range hl.hl {
+		result.Tags[mapIdx] = append([]string(nil), (*sl)...)
+	}
+	if 0 < len(hl.oc) {
+		result.Counts = make(map[string]map[string]int, len(hl.oc))
+		for mapIdx, counts := range hl.oc {
+			result.Counts[mapIdx] = make(map[string]int, len(counts))
+			for id, n := range counts {
+				result.Counts[mapIdx][id] = n
+			}
+		}
+	}
+	if 0 < len(hl.ts) {
+		result.Seen = make(map[string]map[string]TSeen, len(hl.ts))
+		for mapIdx, ids := range hl.ts {
+			result.Seen[mapIdx] = make(map[string]TSeen, len(ids))
+			for id, seen := range ids {
+				result.Seen[mapIdx][id] = seen
+			}
+		}
+	}
+	if 0 < len(hl.al) {
+		result.Aliases = make(map[string]string, len(hl.al))
+		for alias, target := range hl.al {
+			result.Aliases[alias] = target
+		}
 	}
 
-	return file.Write([]byte(hl.string()))
-} // store()
+	return result
+} // storageData()
 
-// Store writes the whole list to the configured file
+// Store writes the whole list to the configured storage
 // returning the number of bytes written and a possible error.
 //
+// When using the default file storage the file is replaced
+// atomically, i.e. a crash while storing leaves the previous
+// file intact. Storing the list also compacts the storage's
+// journal (if any).
+//
 // If there is an error, it will be of type `*PathError`.
 func (hl *THashList) Store() (int, error) {
-	hl.mtx.RLock()
-	defer hl.mtx.RUnlock()
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
 
 	return hl.store()
 } // Store()
@@ -769,10 +1069,10 @@ func (hl *THashList) string() string {
 	})
 	for _, hash := range tmp {
 		sl := hl.hl[hash]
-		result += "[" + hash + "]\n" + sl.String() + "\n"
+		result += "[" + hash + "]\n" + idLines(*sl.sort(), hl.oc[hash], hl.ts[hash]) + "\n"
 	}
 
-	return result
+	return result + aliasString(hl.al)
 } // string()
 
 // String returns the whole list as a linefeed separated string.
@@ -783,17 +1083,95 @@ func (hl *THashList) String() string {
 	return hl.string()
 } // String()
 
+// TagDelete removes `aTag` and its list of IDs.
+//
+// If `aTag` is an alias its target is deleted (the alias itself
+// is kept, see `AliasRemove()`).
+//
+// `aTag` is the #hashtag/@mention to delete (e.g. `#nsfw`).
+func (hl *THashList) TagDelete(aTag string) *THashList {
+	mapIdx, ok := hl.tagIndex(aTag)
+	if !ok {
+		return hl
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	_ = hl.deleteTag(hl.alias(mapIdx)).changed()
+
+	return hl
+} // TagDelete()
+
+// TagRename replaces `aOldTag` by `aNewTag`.
+//
+// If `aNewTag` already exists the IDs of `aOldTag` are merged into
+// its list. Aliases of `aOldTag` (see `AliasAdd()`) become aliases
+// of `aNewTag`.
+//
+// If `aOldTag` is an alias the tag it stands for is renamed, and the
+// alias then points to `aNewTag`. If `aNewTag` is an alias of
+// `aOldTag` that alias is removed, so that e.g. after
+// `AliasAdd("#kubernetes", "#kubernets")` the misspelled tag can
+// still be renamed to `#kubernetes`.
+//
+// This method is intended e.g. to fix misspelled tags.
+//
+// `aOldTag` is the #hashtag/@mention to replace (e.g. `#kubernets`).
+//
+// `aNewTag` is the replacement (e.g. `#kubernetes`); if it doesn't
+// start with a sigil the sigil of `aOldTag` is used.
+func (hl *THashList) TagRename(aOldTag, aNewTag string) *THashList {
+	oldIdx, ok := hl.tagIndex(aOldTag)
+	if !ok {
+		return hl
+	}
+	newIdx, ok := hl.tagIndex(aNewTag)
+	if !ok {
+		if (0 == len(aNewTag)) || hl.isSigil(aNewTag[0]) {
+			return hl
+		}
+		// use the sigil of the old tag
+		newIdx = mapIndex(oldIdx[0], aNewTag)
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	_ = hl.renameTag(oldIdx, newIdx).changed()
+
+	return hl
+} // TagRename()
+
 // `updateID()` checks `aText` removing all #hashtags/@mentions no longer
 // present and adds #hashtags/@mentions new in `aText`.
 //
+// Only the differences between the current lists and `aText`
+// are modified.
+//
 // `aID` is the ID to update.
 //
 // `aText` is the text to use.
 func (hl *THashList) updateID(aID string, aText []byte) *THashList {
 	// the mutex.Lock is done by the caller
-	hl.removeID(aID)
 
-	return hl.parseID(aID, aText)
+	tags := hl.parseTags(aID, aText)
+	found := make(map[string]bool, len(tags))
+	for _, mapIdx := range tags {
+		found[mapIdx] = true
+	}
+	if old, ok := hl.index()[aID]; ok {
+		for _, mapIdx := range append(tSourceList(nil), (*old)...) {
+			if !found[mapIdx] {
+				hl.remove0(mapIdx, aID)
+			}
+		}
+	}
+	for _, mapIdx := range tags {
+		hl.add0(mapIdx, aID)
+	}
+	hl.setCounts(aID, tags)
+	hl.touch(aID, tags...)
+
+	return hl
 } // updateID()
 
 /* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
@@ -820,27 +1198,41 @@ type (
 // If `aFunc` returns `false` when called the respective ID
 // will be removed from the associated #hashtag/@mention.
 //
+// The entries are collected before the first call of `aFunc` and the
+// IDs are removed after the last one, so `aFunc` may call the list's
+// methods (without seeing its own removals, though).
+//
 // `aFunc` is the function called for each ID in all lists.
 func (hl *THashList) Walk(aFunc TWalkFunc) {
-	oldCRC := hl.checksum()
-	defer func() {
-		if oldCRC != atomic.LoadUint32(&hl.µChange) {
-			_, _ = hl.Store()
-		}
-	}()
-
+	type tEntry struct {
+		hash, id string
+	}
+	hl.mtx.RLock()
+	entries := make([]tEntry, 0, len(hl.hl))
 	for hash, sl := range hl.hl {
 		for _, id := range *sl {
-			if aFunc(hash, id) {
-				continue
-			}
-			sl.removeID(id)
-			if 0 == len(*sl) {
-				delete(hl.hl, hash)
-			}
-			atomic.StoreUint32(&hl.µChange, 0)
+			entries = append(entries, tEntry{hash, id})
 		}
 	}
+	hl.mtx.RUnlock()
+
+	var removals []tEntry
+	for _, entry := range entries {
+		if !aFunc(entry.hash, entry.id) {
+			removals = append(removals, entry)
+		}
+	}
+	if 0 == len(removals) {
+		return
+	}
+
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	for _, entry := range removals {
+		hl.remove0(entry.hash, entry.id)
+	}
+	_ = hl.changed()
 } // Walk()
 
 // Walker traverses through all entries in the INI list sections
@@ -860,14 +1252,60 @@ func (hl *THashList) Walker(aWalker THashWalker) {
 // If there is an error, it will be of type *PathError.
 //
 // `aFilename` is the name of the file to use for reading and storing.
-func New(aFilename string) (*THashList, error) {
+//
+// `aOptions` are optional settings like e.g. `WithStorage()`.
+func New(aFilename string, aOptions ...TOption) (*THashList, error) {
 	result := THashList{
 		fn:  aFilename,
 		hl:  make(tHashMap, 64),
+		ix:  make(tHashMap, 64),
 		mtx: new(sync.RWMutex),
 	}
+	if !UseBinaryStorage {
+		result.format = FormatText
+	}
+	for _, option := range aOptions {
+		option(&result)
+	}
 
 	return result.Load()
 } // New()
 
+// WithBackup returns an option to keep the previous generation
+// of the list's file when storing it.
+//
+// The backup file is named like the list's file with an additional
+// `.bak` extension; it is used by `Load()` (and replaces the list's
+// file) if that file turns out to be corrupted or missing.
+//
+// `aBackup` tells whether to keep a backup file.
+func WithBackup(aBackup bool) TOption {
+	return func(aList *THashList) {
+		aList.bak = aBackup
+	}
+} // WithBackup()
+
+// WithFormat returns an option to use `aFormat` when writing
+// the list's file.
+//
+// When reading the file its format is detected automatically.
+//
+// `aFormat` is the file format to use (i.e. either `FormatBinary`
+// or `FormatText`).
+func WithFormat(aFormat TFormat) TOption {
+	return func(aList *THashList) {
+		aList.format = aFormat
+	}
+} // WithFormat()
+
+// WithStorage returns an option to use `aStorage` for reading
+// and storing the list instead of the file given to `New()`.
+//
+// `aStorage` is the persistence backend to use.
+func WithStorage(aStorage TStorage) TOption {
+	return func(aList *THashList) {
+		aList.st = aStorage
+	}
+} // WithStorage()
+
 /* EoF */
@@ -7,28 +7,63 @@
 package hashtags
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func delDB(aFilename string) string {
 	os.Remove(aFilename)
+	os.Remove(aFilename + journalSuffix)
 
 	return aFilename
 } // delDB()
 
+func Test_tSourceList_add(t *testing.T) {
+	sl1 := &tSourceList{}
+	wl1 := &tSourceList{"one"}
+	wl2 := &tSourceList{"one", "two"}
+	wl3 := &tSourceList{"one", "three", "two"}
+	wl4 := &tSourceList{"five", "one", "three", "two"}
+	type args struct {
+		aID string
+	}
+	tests := []struct {
+		name string
+		sl   *tSourceList
+		args args
+		want *tSourceList
+	}{
+		// TODO: Add test cases.
+		{" 1", sl1, args{"one"}, wl1},
+		{" 2", sl1, args{"two"}, wl2},
+		{" 3", sl1, args{"three"}, wl3},
+		{" 4", sl1, args{"two"}, wl3},
+		{" 5", sl1, args{"five"}, wl4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sl.add(tt.args.aID); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tSourceList.add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_tSourceList_add()
+
 func Test_tSourceList_indexOf(t *testing.T) {
 	sl1 := &tSourceList{
+		"five",
+		"four",
 		"one",
-		"two",
 		"three",
-		"four",
-		"five",
+		"two",
 	}
 	type args struct {
 		aID string
@@ -40,12 +75,14 @@ func Test_tSourceList_indexOf(t *testing.T) {
 		want int
 	}{
 		// TODO: Add test cases.
-		{" 1", sl1, args{"one"}, 0},
-		{" 2", sl1, args{"two"}, 1},
-		{" 3", sl1, args{"three"}, 2},
-		{" 4", sl1, args{"four"}, 3},
-		{" 5", sl1, args{"five"}, 4},
+		{" 1", sl1, args{"one"}, 2},
+		{" 2", sl1, args{"two"}, 4},
+		{" 3", sl1, args{"three"}, 3},
+		{" 4", sl1, args{"four"}, 1},
+		{" 5", sl1, args{"five"}, 0},
 		{" 6", sl1, args{"six"}, -1},
+		{" 7", sl1, args{"eight"}, -1},
+		{" 8", sl1, args{"zero"}, -1},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -58,26 +95,26 @@ func Test_tSourceList_indexOf(t *testing.T) {
 
 func Test_tSourceList_removeID(t *testing.T) {
 	sl1 := &tSourceList{
+		"five",
+		"four",
 		"one",
-		"two",
 		"three",
-		"four",
-		"five",
+		"two",
 	}
 	wl1 := &tSourceList{
-		"two",
-		"three",
-		"four",
 		"five",
+		"four",
+		"three",
+		"two",
 	}
 	wl2 := &tSourceList{
-		"two",
-		"three",
 		"four",
+		"three",
+		"two",
 	}
 	wl3 := &tSourceList{
-		"two",
 		"four",
+		"two",
 	}
 	type args struct {
 		aID string
@@ -105,8 +142,8 @@ func Test_tSourceList_removeID(t *testing.T) {
 func Test_tSourceList_renameID(t *testing.T) {
 	sl1 := &tSourceList{
 		"one",
-		"two",
 		"three",
+		"two",
 	}
 	wl1 := &tSourceList{
 		"four",
@@ -168,8 +205,71 @@ func Test_tSourceList_String(t *testing.T) {
 	}
 } // Test_tSourceList_String()
 
+func Test_canonical(t *testing.T) {
+	tests := []struct {
+		name string
+		aTag string
+		want string
+	}{
+		// TODO: Add test cases.
+		{" 1", "#Hash1", "#hash1"},
+		{" 2", "#Straße", "#strasse"},
+		{" 3", "#STRASSE", "#strasse"},
+		{" 4", "#cafe\u0301", "#café"},
+		{" 5", "#CAFÉ", "#café"},
+		{" 6", "#ＦＵＬＬ", "#full"},
+		{" 7", "@ΣΊΣΥΦΟΣ", "@σίσυφοσ"},
+		{" 8", "#Москва", "#москва"},
+		{" 9", "#日本語", "#日本語"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonical(tt.aTag); got != tt.want {
+				t.Errorf("canonical() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // Test_canonical()
+
+func TestTHashList_canonicalKeys(t *testing.T) {
+	hl, _ := New("")
+	hl.HashAdd("#Straße", "id_a").
+		HashAdd("#STRASSE", "id_b").
+		HashAdd("#cafe\u0301", "id_c")
+	if got, want := hl.HashList("#strasse"), []string{"id_a", "id_b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.HashList() = %v, want %v", got, want)
+	}
+	if got, want := hl.HashLen("#CAFÉ"), 1; got != want {
+		t.Errorf("THashList.HashLen() = %d, want %d", got, want)
+	}
+
+	// data written by earlier versions gets merged by `Load()`:
+	ms := &tMemStorage{data: &TStorageData{
+		Tags: map[string][]string{
+			"#straße":     {"id_b", "id_a"},
+			"#strasse":    {"id_c", "id_a"},
+			"#cafe\u0301": {"id_d"},
+			"#café":       {"id_e"},
+		},
+		Journal: []TJournalEntry{
+			{Op: JournalAdd, Tag: "#STRAßE", ID: "id_f"},
+		},
+	}}
+	hl2, _ := New("", WithStorage(ms))
+	if got, want := hl2.Len(), 2; got != want {
+		t.Errorf("THashList.Len() = %d, want %d", got, want)
+	}
+	if got, want := hl2.HashList("#Straße"), []string{"id_a", "id_b", "id_c", "id_f"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.HashList() = %v, want %v", got, want)
+	}
+	if got, want := hl2.HashList("#café"), []string{"id_d", "id_e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.HashList() = %v, want %v", got, want)
+	}
+} // TestTHashList_canonicalKeys()
+
 func TestNew(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	fn2 := delDB("does.not.exist")
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2 := "id_c", "id_a"
@@ -209,6 +309,7 @@ func TestNew(t *testing.T) {
 
 func TestTHashList_Checksum(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2, id3 := "id_c", "id_a", "id_b"
 	hl1 := &THashList{
@@ -225,12 +326,12 @@ func TestTHashList_Checksum(t *testing.T) {
 	w1 := h1a.Checksum()
 	hl2 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id2},
+			hash1: &tSourceList{id2, id1},
 			hash2: &tSourceList{id2, id3},
 		},
 		mtx: new(sync.RWMutex),
 	}
-	h2a, _ := New(fn)
+	h2a, _ := New(delDB(fn))
 	h2a.HashAdd(hash1, id1).
 		HashAdd(hash1, id2).
 		HashAdd(hash2, id2).
@@ -259,6 +360,7 @@ func TestTHashList_Checksum(t *testing.T) {
 
 func TestTHashList_Clear(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2 := "id_c", "id_a"
 	hl1, _ := New(fn)
@@ -289,7 +391,7 @@ func TestTHashList_CountedList(t *testing.T) {
 	hl1 := &THashList{
 		hl: tHashMap{
 			hash1: &tSourceList{id2},
-			hash2: &tSourceList{id1, id3},
+			hash2: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -300,8 +402,8 @@ func TestTHashList_CountedList(t *testing.T) {
 	hl2 := &THashList{
 		hl: tHashMap{
 			hash1: &tSourceList{id2},
-			hash2: &tSourceList{id1, id3},
-			hash3: &tSourceList{id1, id2, id3},
+			hash2: &tSourceList{id3, id1},
+			hash3: &tSourceList{id2, id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -453,12 +555,13 @@ func TestTHashList_HashList(t *testing.T) {
 
 func TestTHashList_HashRemove(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2 := "id_c", "id_a"
 	hl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id2},
-			hash2: &tSourceList{id1, id2},
+			hash1: &tSourceList{id2, id1},
+			hash2: &tSourceList{id2, id1},
 		},
 		fn:  fn,
 		mtx: new(sync.RWMutex),
@@ -466,14 +569,14 @@ func TestTHashList_HashRemove(t *testing.T) {
 	wl1 := &THashList{
 		hl: tHashMap{
 			hash1: &tSourceList{id2},
-			hash2: &tSourceList{id1, id2},
+			hash2: &tSourceList{id2, id1},
 		},
 		fn:  fn,
 		mtx: new(sync.RWMutex),
 	}
 	wl2 := &THashList{
 		hl: tHashMap{
-			hash2: &tSourceList{id1, id2},
+			hash2: &tSourceList{id2, id1},
 		},
 		fn:  fn,
 		mtx: new(sync.RWMutex),
@@ -485,7 +588,12 @@ func TestTHashList_HashRemove(t *testing.T) {
 		fn:  fn,
 		mtx: new(sync.RWMutex),
 	}
-	wl4, _ := New(fn)
+	// an empty list (unlike those created by `New()` without a reverse index):
+	wl4 := &THashList{
+		hl:  tHashMap{},
+		fn:  fn,
+		mtx: new(sync.RWMutex),
+	}
 	type args struct {
 		aHash string
 		aID   string
@@ -517,9 +625,9 @@ func TestTHashList_IDlist(t *testing.T) {
 	id1, id2, id3 := "id_c", "id_a", "id_b"
 	hl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id2},
+			hash1: &tSourceList{id2, id1},
 			hash2: &tSourceList{id2, id3},
-			hash3: &tSourceList{id1, id3},
+			hash3: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -551,15 +659,82 @@ func TestTHashList_IDlist(t *testing.T) {
 	}
 } // TestTHashList_IDlist()
 
+func TestTHashList_index(t *testing.T) {
+	hl, _ := New("")
+	hl.HashAdd("#hash1", "id_a").HashAdd("#hash2", "id_a").
+		MentionAdd("@mention1", "id_b")
+	// build the index before the modifications below:
+	if got := hl.IDlist("id_a"); !reflect.DeepEqual(got, []string{"#hash1", "#hash2"}) {
+		t.Errorf("THashList.IDlist() = %v, want %v", got, []string{"#hash1", "#hash2"})
+	}
+	hl.HashRemove("#hash1", "id_a").HashAdd("#hash3", "id_b").
+		IDrename("id_a", "id_b")
+	type tCase struct {
+		id   string
+		want []string
+	}
+	check := func(aCases []tCase) {
+		for _, tc := range aCases {
+			if got := hl.IDlist(tc.id); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("THashList.IDlist(%q) = %v, want %v", tc.id, got, tc.want)
+			}
+		}
+	}
+	check([]tCase{
+		{"id_a", nil},
+		{"id_b", []string{"#hash2", "#hash3", "@mention1"}},
+	})
+
+	hl.IDupdate("id_b", []byte("only #hash2 and #hash4"))
+	check([]tCase{
+		{"id_b", []string{"#hash2", "#hash4"}},
+	})
+
+	hl.IDremove("id_b")
+	check([]tCase{
+		{"id_b", nil},
+	})
+	if got := hl.LenTotal(); 0 != got {
+		t.Errorf("THashList.LenTotal() = %d, want %d", got, 0)
+	}
+} // TestTHashList_index()
+
+func TestTHashList_indexReadLock(t *testing.T) {
+	fn := delDB("index.db")
+	defer delDB(fn)
+	hl1, _ := New(fn)
+	hl1.HashAdd("#hash1", "id_a").MentionAdd("@mention1", "id_a")
+	// the index is built when loading the list:
+	hl2, _ := New(fn)
+	if got, want := hl2.ix, hl1.ix; !reflect.DeepEqual(got, want) {
+		t.Errorf("New() index = %v, want %v", got, want)
+	}
+	// reading the index must not wait for other readers:
+	hl2.mtx.RLock()
+	defer hl2.mtx.RUnlock()
+	done := make(chan bool)
+	go func() {
+		hl2.IDlist("id_a")
+		_, _ = hl2.Query("#hash1")
+		hl2.TagRanked('#', "hash1")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Errorf("THashList.IDlist() blocked by a read lock")
+	}
+} // TestTHashList_indexReadLock()
+
 func TestTHashList_IDremove(t *testing.T) {
 	// fn := delDB("hashlist.db")
 	hash1, hash2, hash3 := "#hash1", "#hash2", "#hash3"
 	id1, id2, id3 := "id_c", "id_a", "id_b"
 	hl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id3},
+			hash1: &tSourceList{id3, id1},
 			hash2: &tSourceList{id2, id3},
-			hash3: &tSourceList{id1, id3},
+			hash3: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -596,7 +771,7 @@ func TestTHashList_IDremove(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.hl.IDremove(tt.args.aID); !reflect.DeepEqual(got, tt.want) {
+			if got := tt.hl.IDremove(tt.args.aID); got.String() != tt.want.String() {
 				t.Errorf("THashList.IDremove() = %v, want %v", got, tt.want)
 			}
 		})
@@ -655,7 +830,7 @@ func TestTHashList_IDrename(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := tt.hl.IDrename(tt.args.aOldID, tt.args.aNewID); !reflect.DeepEqual(got, tt.want) {
+			if got := tt.hl.IDrename(tt.args.aOldID, tt.args.aNewID); got.String() != tt.want.String() {
 				t.Errorf("THashList.IDrename() = %v, want %v", got, tt.want)
 			}
 		})
@@ -667,15 +842,15 @@ func TestTHashList_IDupdate(t *testing.T) {
 	id1, id2, id3 := "id_c", "id_a", "id_b"
 	hl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id2, id3},
-			hash2: &tSourceList{id1, id2},
+			hash1: &tSourceList{id2, id3, id1},
+			hash2: &tSourceList{id2, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
 	tx1 := []byte("blabla " + hash1 + " blabla " + hash3 + " blabla")
 	wl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id2, id3},
+			hash1: &tSourceList{id2, id3, id1},
 			hash2: &tSourceList{id2},
 			hash3: &tSourceList{id1},
 		},
@@ -684,7 +859,7 @@ func TestTHashList_IDupdate(t *testing.T) {
 	tx2 := []byte("blabla blabla blabla")
 	wl2 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id3},
+			hash1: &tSourceList{id3, id1},
 			hash3: &tSourceList{id1},
 		},
 		mtx: new(sync.RWMutex),
@@ -714,12 +889,13 @@ func TestTHashList_IDupdate(t *testing.T) {
 
 func TestTHashList_Len(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hl1, _ := New(fn)
 	hl2, _ := New(fn)
 	hl2.HashAdd("#hash", "source")
-	hl3, _ := New(fn)
+	hl3, _ := New(delDB(fn))
 	hl3.HashAdd("#hash2", "source1")
-	hl4, _ := New(fn)
+	hl4, _ := New(delDB(fn))
 	hl4.HashAdd("#hash2", "source1").
 		HashAdd("#hash3", "source2")
 	tests := []struct {
@@ -744,6 +920,7 @@ func TestTHashList_Len(t *testing.T) {
 
 func TestTHashList_LenTotal(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2, hash3 := "#hash1", "#hash2", "#hash3"
 	id1, id2, id3 := "id_c", "id_a", "id_b"
 	hl1, _ := New(fn)
@@ -784,6 +961,7 @@ func TestTHashList_Load(t *testing.T) {
 	// hl1.SetFilename("load.db")
 	// hl1.store()
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	fn2 := delDB(".does.not.exist")
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2 := "id_c", "id_a"
@@ -910,6 +1088,42 @@ func TestTHashList_parseID(t *testing.T) {
 			}
 		})
 	}
+
+	// regression table of words which must not be taken as tags:
+	rtests := []struct {
+		name  string
+		aText string
+		want  []string
+	}{
+		{"email", "mail user@example.com or @bob", []string{"@bob"}},
+		{"email plus", "first.last+tag@sub.example.org", nil},
+		{"url fragment", "see https://site/page#section #tag", []string{"#tag"}},
+		{"url slash fragment", "https://site/#/route and http://x.org/a?b=#c", nil},
+		{"url mention", "follow https://social.example/@user now", nil},
+		{"www url", "www.example.com/#top #top", []string{"#top"}},
+		{"mailto", "mailto:bob@example.com", nil},
+		{"word fragment", "page#section and a#b", nil},
+		{"entity", "it&#39;s #fine", []string{"#fine"}},
+		{"colour 3", "color: #fff;", nil},
+		{"colour 6", "#ff0000 and #C0FFEE", nil},
+		{"colour 8", "#ff000080", nil},
+		{"colour zeros", "#000 #000000", nil},
+		{"hex words", "#cafe #facade #add", []string{"#add", "#cafe", "#facade"}},
+		{"numbers", "#2024 #123456", []string{"#123456", "#2024"}},
+		{"not hex", "#fffg #ff00zz", []string{"#ff00zz", "#fffg"}},
+		{"short hex words", "#B2B sales on a #C64 or an #A380 at #1A2B", []string{"#1a2b", "#a380", "#b2b", "#c64"}},
+		{"short colours", "color: #C64; border: 1px solid #a380;", nil},
+		{"short hex list", "(#B2B, #1a2b)", []string{"#1a2b", "#b2b"}},
+		{"markup", "**#bold** _#italic_ (#paren)", []string{"#bold", "#italic", "#paren"}},
+	}
+	for _, tt := range rtests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl, _ := New("")
+			if got := hl.parseID("id", []byte(tt.aText)).IDlist("id"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.parseID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
 } // TestTHashList_parseID()
 
 func TestTHashList_remove(t *testing.T) {
@@ -918,9 +1132,9 @@ func TestTHashList_remove(t *testing.T) {
 	id1, id2, id3 := "id_3", "id_1", "id_2"
 	hl1 := &THashList{
 		hl: tHashMap{
-			hash1: &tSourceList{id1, id3},
+			hash1: &tSourceList{id3, id1},
 			hash2: &tSourceList{id2, id3},
-			hash3: &tSourceList{id1, id3},
+			hash3: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -928,7 +1142,7 @@ func TestTHashList_remove(t *testing.T) {
 		hl: tHashMap{
 			hash1: &tSourceList{id3},
 			hash2: &tSourceList{id2, id3},
-			hash3: &tSourceList{id1, id3},
+			hash3: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -936,7 +1150,7 @@ func TestTHashList_remove(t *testing.T) {
 		hl: tHashMap{
 			hash1: &tSourceList{id3},
 			hash2: &tSourceList{id3},
-			hash3: &tSourceList{id1, id3},
+			hash3: &tSourceList{id3, id1},
 		},
 		mtx: new(sync.RWMutex),
 	}
@@ -967,7 +1181,11 @@ func TestTHashList_remove(t *testing.T) {
 		},
 		mtx: new(sync.RWMutex),
 	}
-	wl7, _ := New("")
+	// an empty list (unlike those created by `New()` without a reverse index):
+	wl7 := &THashList{
+		hl:  tHashMap{},
+		mtx: new(sync.RWMutex),
+	}
 	type args struct {
 		aDelim  byte
 		aMapIdx string
@@ -999,6 +1217,7 @@ func TestTHashList_remove(t *testing.T) {
 
 func TestTHashList_store(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2 := "#hash1", "#Zensurheberrecht"
 	id1, id2 := "id_c", "id_a"
 	hl1, _ := New(fn)
@@ -1018,7 +1237,7 @@ func TestTHashList_store(t *testing.T) {
 		wantErr bool
 	}{
 		// TODO: Add test cases.
-		{" 1", hl1, 91, false},
+		{" 1", hl1, 152, false},
 		{" 2", hl2, 0, true},
 	}
 	for _, tt := range tests {
@@ -1037,6 +1256,7 @@ func TestTHashList_store(t *testing.T) {
 
 func TestTHashList_String(t *testing.T) {
 	fn := delDB("hashlist.db")
+	defer delDB(fn)
 	hash1, hash2 := "#hash1", "#hash2"
 	id1, id2 := "id_c", "id_a"
 	hl1, _ := New(fn)
@@ -1063,6 +1283,109 @@ func TestTHashList_String(t *testing.T) {
 	}
 } // TestTHashList_String()
 
+func TestTHashList_TagDelete(t *testing.T) {
+	fn := delDB("tagdelete.db")
+	defer delDB(fn)
+	hl1, _ := New(fn)
+	hl1.IDparse("id_a", []byte("#nsfw #fine @bob")).
+		IDparse("id_b", []byte("#NSFW")).
+		AliasAdd("#porn", "#nsfw")
+	_ = hl1.CountedList() // fill the cache
+	tests := []struct {
+		name string
+		aTag string
+		want []TCountItem
+	}{
+		// TODO: Add test cases.
+		{" 1", "#unknown", []TCountItem{{1, "@bob"}, {1, "#fine"}, {2, "#nsfw"}}},
+		{" 2", "nsfw", []TCountItem{{1, "@bob"}, {1, "#fine"}, {2, "#nsfw"}}},
+		{" 3", "#Porn", []TCountItem{{1, "@bob"}, {1, "#fine"}}},
+		{" 4", "@bob", []TCountItem{{1, "#fine"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl1.TagDelete(tt.aTag).CountedList(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagDelete() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got, want := hl1.IDlist("id_a"), []string{"#fine"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.IDlist() = %v, want %v", got, want)
+	}
+	// the journal must reflect the deletions:
+	hl2, _ := New(fn)
+	if got, want := hl2.String(), hl1.String(); got != want {
+		t.Errorf("New() = %v, want %v", got, want)
+	}
+} // TestTHashList_TagDelete()
+
+func TestTHashList_TagRename(t *testing.T) {
+	fn := delDB("tagrename.db")
+	defer delDB(fn)
+	hl1, _ := New(fn)
+	hl1.IDparse("id_a", []byte("#kubernets @bob")).
+		IDparse("id_b", []byte("#Kubernetes")).
+		IDparse("id_c", []byte("#kubernets #k8s")).
+		AliasAdd("#kube", "#kubernets")
+	_ = hl1.CountedList() // fill the cache
+	tests := []struct {
+		name    string
+		aOldTag string
+		aNewTag string
+		want    []TCountItem
+	}{
+		{" 1", "#unknown", "#kubernetes", []TCountItem{{1, "@bob"}, {1, "#k8s"}, {1, "#kubernetes"}, {2, "#kubernets"}}},
+		{" 2", "#kubernets", "kubernetes", []TCountItem{{1, "@bob"}, {1, "#k8s"}, {3, "#kubernetes"}}},
+		{" 3", "@bob", "@Robert", []TCountItem{{1, "#k8s"}, {3, "#kubernetes"}, {1, "@robert"}}},
+		{" 4", "#k8s", "#k8s", []TCountItem{{1, "#k8s"}, {3, "#kubernetes"}, {1, "@robert"}}},
+		{" 5", "#k8s", "", []TCountItem{{1, "#k8s"}, {3, "#kubernetes"}, {1, "@robert"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl1.TagRename(tt.aOldTag, tt.aNewTag).CountedList(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagRename() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got, want := hl1.IDlist("id_c"), []string{"#k8s", "#kubernetes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.IDlist() = %v, want %v", got, want)
+	}
+	if got, want := hl1.Aliases(), map[string]string{"#kube": "#kubernetes"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Aliases() = %v, want %v", got, want)
+	}
+	// the journal must reflect the renaming:
+	hl2, _ := New(fn)
+	if got, want := hl2.String(), hl1.String(); got != want {
+		t.Errorf("New() = %v, want %v", got, want)
+	}
+
+	// renaming an alias renames the tag it stands for:
+	hl1.TagRename("#kube", "#k9s")
+	if got, want := hl1.CountedList(), []TCountItem{{1, "#k8s"}, {3, "#k9s"}, {1, "@robert"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.TagRename() = %v, want %v", got, want)
+	}
+	if got, want := hl1.Aliases(), map[string]string{"#kube": "#k9s"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Aliases() = %v, want %v", got, want)
+	}
+
+	// renaming a tag to one of its aliases removes that alias:
+	hl1.AliasAdd("#k8s-io", "#k9s").
+		TagRename("#k9s", "#k8s-io")
+	if got, want := hl1.CountedList(), []TCountItem{{1, "#k8s"}, {3, "#k8s-io"}, {1, "@robert"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.TagRename() = %v, want %v", got, want)
+	}
+	if got, want := hl1.Aliases(), map[string]string{"#kube": "#k8s-io"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Aliases() = %v, want %v", got, want)
+	}
+	hl3, _ := New(fn)
+	if got, want := hl3.String(), hl1.String(); got != want {
+		t.Errorf("New() = %v, want %v", got, want)
+	}
+	if got, want := hl3.Aliases(), hl1.Aliases(); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Aliases() = %v, want %v", got, want)
+	}
+} // TestTHashList_TagRename()
+
 func Benchmark_LoadTxT(b *testing.B) {
 	hl, _ := New("")
 	hl.SetFilename("load.txt")
@@ -1112,3 +1435,78 @@ func Benchmark_StoreBin(b *testing.B) {
 		}
 	}
 } // Benchmark_StoreBin()
+
+// `benchIDs()` returns `aCount` IDs in random order.
+func benchIDs(aCount int) []string {
+	result := make([]string, aCount)
+	for i := range result {
+		// multiplying by a prime scatters the IDs
+		result[i] = fmt.Sprintf("id_%08d", (i*7919)%aCount)
+	}
+
+	return result
+} // benchIDs()
+
+// `addLinear()` is the former insertion algorithm (linear duplicate
+// check followed by a full sort) used as the benchmarks' baseline.
+func addLinear(aList *tSourceList, aID string) {
+	for _, id := range *aList {
+		if id == aID {
+			return
+		}
+	}
+	*aList = append(*aList, aID)
+	sort.Slice(*aList, func(i, j int) bool {
+		return ((*aList)[i] < (*aList)[j])
+	})
+} // addLinear()
+
+func Benchmark_tSourceList_add(b *testing.B) {
+	ids := benchIDs(10000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		sl := make(tSourceList, 0, 32)
+		for _, id := range ids {
+			sl.add(id)
+		}
+	}
+} // Benchmark_tSourceList_add()
+
+func Benchmark_tSourceList_addLinear(b *testing.B) {
+	ids := benchIDs(10000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		sl := make(tSourceList, 0, 32)
+		for _, id := range ids {
+			addLinear(&sl, id)
+		}
+	}
+} // Benchmark_tSourceList_addLinear()
+
+func Benchmark_tSourceList_removeID(b *testing.B) {
+	ids := benchIDs(10000)
+	sorted := append(tSourceList(nil), ids...)
+	sorted.sort()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		sl := append(tSourceList(nil), sorted...)
+		for _, id := range ids {
+			sl.removeID(id)
+		}
+	}
+} // Benchmark_tSourceList_removeID()
+
+func Benchmark_HashAdd(b *testing.B) {
+	ids := benchIDs(10000)
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		hl, _ := New("")
+		for _, id := range ids {
+			hl.HashAdd("#popular", id)
+		}
+	}
+} // Benchmark_HashAdd()
@@ -0,0 +1,216 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Characters which can't be used as hierarchy separators since
+// they're sigils, part of the query language or part of tags.
+const hierarchyReserved = "!\"#$%&'()*+-@[]^_`|~"
+
+// `isDescendant()` returns whether the list index `aIndex` is a
+// descendant of `aParent` (i.e. starts with `aParent` followed by
+// the separator `aSep`).
+func isDescendant(aIndex, aParent string, aSep byte) bool {
+	return (len(aIndex) > len(aParent)+1) &&
+		(aSep == aIndex[len(aParent)]) &&
+		strings.HasPrefix(aIndex, aParent)
+} // isDescendant()
+
+// `treeIndex()` returns the list index of `aTag` without trailing
+// separators (with aliases resolved).
+//
+// `aSigil` is the tag's first character (e.g. '#' or '@').
+//
+// `aTag` is the tag to lookup.
+func (hl *THashList) treeIndex(aSigil byte, aTag string) string {
+	// the mutex.Lock is done by the callers
+
+	if 0 == len(aTag) {
+		return ""
+	}
+	result := mapIndex(aSigil, aTag)
+	if 0 != hl.sep {
+		result = strings.TrimRight(result, string(hl.sep))
+	}
+	if 1 >= len(result) {
+		return ""
+	}
+
+	return hl.alias(result)
+} // treeIndex()
+
+// `treeIDs()` returns the sorted list of IDs of `aIndex` and all its
+// descendants.
+//
+// `aIndex` is the list index of the subtree's root.
+func (hl *THashList) treeIDs(aIndex string) []string {
+	// the mutex.Lock is done by the callers
+
+	if 0 == hl.sep {
+		if sl, ok := hl.hl[aIndex]; ok {
+			return append([]string(nil), (*sl)...)
+		}
+		return []string{}
+	}
+	ids := make(map[string]struct{})
+	for key, sl := range hl.hl {
+		if (key == aIndex) || isDescendant(key, aIndex, hl.sep) {
+			for _, id := range *sl {
+				ids[id] = struct{}{}
+			}
+		}
+	}
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+
+	return result
+} // treeIDs()
+
+// `tokenSigils()` returns the sigils the list's tokenizer should use.
+func (hl *THashList) tokenSigils() []TSigil {
+	if 0 == hl.sep {
+		return hl.sigils
+	}
+	result := append([]TSigil(nil), hl.sigilList()...)
+	for idx := range result {
+		result[idx].sep = hl.sep
+	}
+
+	return result
+} // tokenSigils()
+
+// TagChildren returns the sorted list of the direct children of `aTag`.
+//
+// A child is returned even if it isn't used by itself but only by
+// its descendants (e.g. with only `#lang/go/generics` in the list
+// the children of `#lang` are `[#lang/go]`).
+// Without a hierarchy separator (see `WithHierarchy()`) the result
+// is always `nil`.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the parent tag.
+func (hl *THashList) TagChildren(aSigil byte, aTag string) (rList []string) {
+	if (0 == hl.sep) || !hl.isSigil(aSigil) {
+		return
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	parent := hl.treeIndex(aSigil, aTag)
+	if 0 == len(parent) {
+		return
+	}
+
+	seen := make(map[string]bool)
+	for key := range hl.hl {
+		if !isDescendant(key, parent, hl.sep) {
+			continue
+		}
+		child := key
+		if idx := strings.IndexByte(key[len(parent)+1:], hl.sep); 0 <= idx {
+			child = key[:len(parent)+1+idx]
+		}
+		if !seen[child] {
+			seen[child] = true
+			rList = append(rList, child)
+		}
+	}
+	sort.Strings(rList)
+
+	return
+} // TagChildren()
+
+// TagTreeLen returns the number of (distinct) IDs stored for `aTag`
+// and all its descendants (or `-1` if there's no such tag).
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the subtree's root.
+func (hl *THashList) TagTreeLen(aSigil byte, aTag string) int {
+	if !hl.isSigil(aSigil) {
+		return -1
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	index := hl.treeIndex(aSigil, aTag)
+	if 0 == len(index) {
+		return -1
+	}
+
+	if ids := hl.treeIDs(index); 0 < len(ids) {
+		return len(ids)
+	}
+
+	return -1
+} // TagTreeLen()
+
+// TagTreeList returns the sorted list of IDs associated with `aTag`
+// and – if `aDescendants` is `true` – all its descendants.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the subtree's root.
+//
+// `aDescendants` tells whether to include the IDs of the tag's
+// descendants (e.g. `#lang/go` and `#lang/go/generics` for `#lang`).
+func (hl *THashList) TagTreeList(aSigil byte, aTag string, aDescendants bool) []string {
+	if !aDescendants {
+		return hl.TagList(aSigil, aTag)
+	}
+	if !hl.isSigil(aSigil) {
+		return nil
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	index := hl.treeIndex(aSigil, aTag)
+	if 0 == len(index) {
+		return nil
+	}
+
+	if ids := hl.treeIDs(index); 0 < len(ids) {
+		return ids
+	}
+
+	return nil
+} // TagTreeList()
+
+// WithHierarchy returns an option to use `aSeparator` to build
+// hierarchical tags like `#lang/go/generics`.
+//
+// The list's tokenizer then accepts `aSeparator` inside of tags and
+// `TagChildren()`, `TagTreeLen()`, `TagTreeList()` and `Query()`
+// (with terms like `#lang/*`) can be used to handle whole subtrees.
+//
+// `aSeparator` must be an ASCII punctuation character other than
+// one of `!"#$%&'()*+-@[]^_|~` and the backtick; otherwise the option
+// is ignored.
+func WithHierarchy(aSeparator byte) TOption {
+	return func(aList *THashList) {
+		if (0x80 <= aSeparator) || strings.ContainsRune(hierarchyReserved, rune(aSeparator)) {
+			return
+		}
+		if !unicode.IsPunct(rune(aSeparator)) && !unicode.IsSymbol(rune(aSeparator)) {
+			return
+		}
+		aList.sep = aSeparator
+	}
+} // WithHierarchy()
+
+/* _EoF_ */
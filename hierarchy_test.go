@@ -0,0 +1,141 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithHierarchy(t *testing.T) {
+	tests := []struct {
+		name  string
+		sep   byte
+		aText string
+		want  []string
+	}{
+		{" 0", 0, "#lang/go", []string{"#lang"}},
+		{" 1", '/', "#lang/go/generics", []string{"#lang/go/generics"}},
+		{" 2", '/', "#lang/go/ and #lang//go", []string{"#lang/go", "#lang"}},
+		{" 3", '.', "I like #lang.go.", []string{"#lang.go"}},
+		{" 4", '/', "https://host/#lang/go", nil},
+		{" 5", '-', "#lang/go", []string{"#lang"}},
+		{" 6", '#', "#lang#go", []string{"#lang"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl, _ := New("", WithHierarchy(tt.sep))
+			if got := matchTags(hl.Matches([]byte(tt.aText))); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithHierarchy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestWithHierarchy()
+
+func TestTHashList_TagChildren(t *testing.T) {
+	hl, _ := New("", WithHierarchy('/'))
+	hl.IDparse("id_a", []byte("#lang/go/generics and #Lang/Rust")).
+		IDparse("id_b", []byte("#lang/go #lang")).
+		IDparse("id_c", []byte("#language, #lang/go/")).
+		IDparse("id_d", []byte("@team/backend"))
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		want   []string
+	}{
+		{" 1", '#', "lang", []string{"#lang/go", "#lang/rust"}},
+		{" 2", '#', "#lang/go/", []string{"#lang/go/generics"}},
+		{" 3", '#', "#lang/go/generics", nil},
+		{" 4", '@', "team", []string{"@team/backend"}},
+		{" 5", '#', "team", nil},
+		{" 6", '#', "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagChildren(tt.aSigil, tt.aTag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagChildren() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_TagChildren()
+
+func TestTHashList_TagTreeList(t *testing.T) {
+	hl, _ := New("", WithHierarchy('/'))
+	hl.IDparse("id_a", []byte("#lang/go/generics and #Lang/Rust")).
+		IDparse("id_b", []byte("#lang/go #lang")).
+		IDparse("id_c", []byte("#language, #lang/go/")).
+		IDparse("id_d", []byte("@team/backend"))
+	type tArgs struct {
+		aSigil       byte
+		aTag         string
+		aDescendants bool
+	}
+	tests := []struct {
+		name    string
+		args    tArgs
+		want    []string
+		wantLen int
+	}{
+		{" 1", tArgs{'#', "#lang", false}, []string{"id_b"}, 3},
+		{" 2", tArgs{'#', "#lang", true}, []string{"id_a", "id_b", "id_c"}, 3},
+		{" 3", tArgs{'#', "lang/go", true}, []string{"id_a", "id_b", "id_c"}, 3},
+		{" 4", tArgs{'#', "lang/go/generics", true}, []string{"id_a"}, 1},
+		{" 5", tArgs{'@', "team", true}, []string{"id_d"}, 1},
+		{" 6", tArgs{'@', "team", false}, nil, 1},
+		{" 7", tArgs{'#', "lan", true}, nil, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagTreeList(tt.args.aSigil, tt.args.aTag, tt.args.aDescendants); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagTreeList() = %v, want %v", got, tt.want)
+			}
+			if got := hl.TagTreeLen(tt.args.aSigil, tt.args.aTag); got != tt.wantLen {
+				t.Errorf("THashList.TagTreeLen() = %v, want %v", got, tt.wantLen)
+			}
+		})
+	}
+	// the result is a copy:
+	hl.TagTreeList('#', "lang", false)[0] = "id_x"
+	if got, want := hl.HashList("#lang"), []string{"id_b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.TagTreeList() = %v, want %v", got, want)
+	}
+} // TestTHashList_TagTreeList()
+
+func TestTHashList_treeQuery(t *testing.T) {
+	hl, _ := New("", WithHierarchy('/'))
+	hl.IDparse("id_a", []byte("#lang/go/generics and #Lang/Rust")).
+		IDparse("id_b", []byte("#lang/go #lang")).
+		IDparse("id_c", []byte("#language, #lang/go/")).
+		IDparse("id_d", []byte("@team/backend"))
+	tests := []struct {
+		name    string
+		aQuery  string
+		want    []string
+		wantErr bool
+	}{
+		{" 1", "#lang", []string{"id_b"}, false},
+		{" 2", "#lang/*", []string{"id_a", "id_b", "id_c"}, false},
+		{" 3", "#lang/go/* NOT #lang", []string{"id_a", "id_c"}, false},
+		{" 4", "#lang/* AND #language", []string{"id_c"}, false},
+		{" 5", "#/*", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hl.Query(tt.aQuery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("THashList.Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_treeQuery()
+
+/* _EoF_ */
@@ -0,0 +1,266 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"html"
+	"strings"
+)
+
+type (
+	// `tHTMLText` is a text node of a HTML document.
+	tHTMLText struct {
+		pos  int    // byte offset of the raw text in the document
+		raw  []byte // the text as found in the document
+		text string // the text with all entities decoded
+	}
+
+	// `tHTMLTokenizer` is a tokenizer handling HTML documents.
+	tHTMLTokenizer struct {
+		sigils []TSigil // kinds of tags to find (nil: the default)
+	}
+)
+
+// `htmlSkipped` lists the elements whose contents are not indexed.
+var htmlSkipped = map[string]bool{
+	"code":   true,
+	"pre":    true,
+	"script": true,
+	"style":  true,
+}
+
+// `htmlIsLetter()` returns whether `aChar` is an ASCII letter.
+func htmlIsLetter(aChar byte) bool {
+	return (('a' <= aChar) && ('z' >= aChar)) || (('A' <= aChar) && ('Z' >= aChar))
+} // htmlIsLetter()
+
+// `htmlTagEnd()` returns the position following the '>' closing the
+// tag starting at `aPos` (or the document's length if there's none).
+//
+// Quoted attribute values may contain '>' characters.
+func htmlTagEnd(aDoc []byte, aPos int) int {
+	var quote byte
+	for idx := aPos; idx < len(aDoc); idx++ {
+		switch c := aDoc[idx]; {
+		case 0 != quote:
+			if c == quote {
+				quote = 0
+			}
+		case ('"' == c) || ('\'' == c):
+			quote = c
+		case '>' == c:
+			return idx + 1
+		}
+	}
+
+	return len(aDoc)
+} // htmlTagEnd()
+
+// `htmlTexts()` returns the text nodes of `aDoc` which are not part
+// of comments or skipped elements (see `htmlSkipped`).
+//
+// `aDoc` is the HTML document to split.
+func htmlTexts(aDoc []byte) (rList []tHTMLText) {
+	var (
+		skip  string // name of the element being skipped
+		depth int    // nesting level of `skip` elements
+		start int    // start of the current text node
+	)
+	addText := func(aEnd int) {
+		if (0 == depth) && (start < aEnd) {
+			raw := aDoc[start:aEnd]
+			rList = append(rList, tHTMLText{
+				pos:  start,
+				raw:  raw,
+				text: html.UnescapeString(string(raw)),
+			})
+		}
+	}
+	for idx := 0; idx < len(aDoc); {
+		if '<' != aDoc[idx] {
+			idx++
+			continue
+		}
+		next := idx + 1
+		if next >= len(aDoc) {
+			break
+		}
+		var end int
+		switch c := aDoc[next]; {
+		case bytes.HasPrefix(aDoc[next:], []byte("!--")):
+			if end = bytes.Index(aDoc[next+3:], []byte("-->")); 0 > end {
+				end = len(aDoc)
+			} else {
+				end += next + 3 + 3
+			}
+
+		case ('!' == c) || ('?' == c):
+			end = htmlTagEnd(aDoc, next)
+
+		case ('/' == c) && (next+1 < len(aDoc)) && htmlIsLetter(aDoc[next+1]):
+			end = htmlTagEnd(aDoc, next)
+			if 0 < depth {
+				if name := htmlTagName(aDoc[next+1:]); name == skip {
+					depth--
+					if 0 == depth {
+						// the end tag starts a new text node
+						start = end
+					}
+				}
+				idx = end
+				continue
+			}
+
+		case htmlIsLetter(c):
+			end = htmlTagEnd(aDoc, next)
+			name := htmlTagName(aDoc[next:])
+			if 0 < depth {
+				if (name == skip) && ('/' != aDoc[end-2]) {
+					depth++
+				}
+				idx = end
+				continue
+			}
+			if htmlSkipped[name] && ('/' != aDoc[end-2]) {
+				addText(idx)
+				skip, depth = name, 1
+				if ("script" == name) || ("style" == name) {
+					// raw text elements: the contents may contain '<'
+					stop := bytes.Index(bytes.ToLower(aDoc[end:]), []byte("</"+name))
+					if 0 > stop {
+						return
+					}
+					end += stop
+				}
+				idx = end
+				continue
+			}
+
+		default:
+			// a literal '<' which is part of the text
+			idx = next
+			continue
+		}
+		addText(idx)
+		idx, start = end, end
+	}
+	addText(len(aDoc))
+
+	return
+} // htmlTexts()
+
+// `htmlTagName()` returns the lower-cased name of the tag starting
+// at `aTag`.
+func htmlTagName(aTag []byte) string {
+	end := 0
+	for (end < len(aTag)) && (htmlIsLetter(aTag[end]) ||
+		(('0' <= aTag[end]) && ('9' >= aTag[end]))) {
+		end++
+	}
+
+	return strings.ToLower(string(aTag[:end]))
+} // htmlTagName()
+
+// `offsets()` returns the byte offsets in the node's raw text of
+// each byte in the node's decoded text (plus one for the end of the
+// text).
+func (ht *tHTMLText) offsets() []int {
+	result := make([]int, 0, len(ht.text)+1)
+	for idx := 0; idx < len(ht.raw); {
+		if '&' == ht.raw[idx] {
+			end := idx + 1
+			for (end < len(ht.raw)) && (end-idx < 32) &&
+				(htmlIsLetter(ht.raw[end]) || ('#' == ht.raw[end]) ||
+					(('0' <= ht.raw[end]) && ('9' >= ht.raw[end]))) {
+				end++
+			}
+			if (end < len(ht.raw)) && (';' == ht.raw[end]) {
+				end++
+			}
+			entity := string(ht.raw[idx:end])
+			if decoded := html.UnescapeString(entity); decoded != entity {
+				// `decoded` might be longer than the entity's name
+				// leaves as literal text (e.g. `&ampx`):
+				for n := 0; n < len(decoded); n++ {
+					result = append(result, idx)
+				}
+				idx = end
+				continue
+			}
+		}
+		result = append(result, idx)
+		idx++
+	}
+	result = append(result, len(ht.raw))
+
+	return result
+} // offsets()
+
+// Match returns the #hashtags and @mentions found in the text nodes
+// of the HTML document `aText` along with their positions.
+//
+// The positions refer to the document itself, i.e. a match's `Length`
+// includes all character entities used to spell the tag.
+//
+// (Implements `TMatcher` interface)
+func (ht tHTMLTokenizer) Match(aText []byte) (rList []TMatch) {
+	for _, node := range htmlTexts(aText) {
+		matches := textMatches([]byte(node.text), sigilsOf(ht.sigils))
+		if 0 == len(matches) {
+			continue
+		}
+		var offsets []int
+		if node.text != string(node.raw) {
+			if offsets = node.offsets(); len(offsets) != len(node.text)+1 {
+				// shouldn't happen: use the node's position instead
+				for idx := range matches {
+					matches[idx].Offset, matches[idx].Length = 0, len(node.raw)
+				}
+				offsets = nil
+			}
+		}
+		for _, match := range matches {
+			if nil != offsets {
+				start := offsets[match.Offset]
+				match.Length = offsets[match.Offset+len(match.Tag)] - start
+				match.Offset = start
+			}
+			match.Offset += node.pos
+			rList = append(rList, match)
+		}
+	}
+
+	return setRuneOffsets(aText, rList)
+} // Match()
+
+// Tokenize returns the #hashtags and @mentions found in the text
+// nodes of the HTML document `aText`.
+//
+// (Implements `TTokenizer` interface)
+func (ht tHTMLTokenizer) Tokenize(aText []byte) []string {
+	return matchTags(ht.Match(aText))
+} // Tokenize()
+
+// `withSigils()` returns a copy of the tokenizer using `aSigils`.
+func (ht tHTMLTokenizer) withSigils(aSigils []TSigil) TTokenizer {
+	return tHTMLTokenizer{sigils: aSigils}
+} // withSigils()
+
+// NewHTMLTokenizer returns a tokenizer handling HTML documents.
+//
+// Only the document's text nodes are searched (after decoding all
+// character entities); tags and their attributes (e.g. `href` or
+// `src` URLs), comments and the contents of `script`, `style`,
+// `code` and `pre` elements are ignored.
+func NewHTMLTokenizer() TTokenizer {
+	return tHTMLTokenizer{}
+} // NewHTMLTokenizer()
+
+/* _EoF_ */
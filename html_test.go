@@ -0,0 +1,47 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tHTMLTokenizer_Tokenize(t *testing.T) {
+	tok := NewHTMLTokenizer()
+	tests := []struct {
+		name  string
+		aText string
+		want  []string
+	}{
+		// TODO: Add test cases.
+		{"text", "<p>Some #text by @bob</p>", []string{"#text", "@bob"}},
+		{"nodes", "<p>#one</p><p>#two</p>", []string{"#one", "#two"}},
+		{"attributes", `<a href="page.html#anchor" title="#no">#yes</a><img src="a#b.png">`, []string{"#yes"}},
+		{"quoted gt", `<a title="a > #no">#yes</a>`, []string{"#yes"}},
+		{"style", "<style>#id { color: #fff; }</style><p>#styled</p>", []string{"#styled"}},
+		{"script", "<script>if (a<b) { x = '#no'; }</script>#yes", []string{"#yes"}},
+		{"code", "<p>Use <code>#include</code> in #c</p>", []string{"#c"}},
+		{"pre", "<pre>#no <pre>#nested</pre> #still</pre>#yes", []string{"#yes"}},
+		{"comment", "<!-- #hidden --><p>#visible</p>", []string{"#visible"}},
+		{"entities", "<p>caf&eacute; &#35;decoded &amp; #caf&eacute;</p>", []string{"#decoded", "#café"}},
+		{"numeric entity", "<p>it&#39;s #fine</p>", []string{"#fine"}},
+		{"literal lt", "<p>1 < 2 #math</p>", []string{"#math"}},
+		{"doctype", "<!DOCTYPE html><html><body>#tag</body></html>", []string{"#tag"}},
+		{"upper case", "<STYLE>#x{}</STYLE><B>#bold</B>", []string{"#bold"}},
+		{"self-closing", "<code/>#yes", []string{"#yes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Tokenize([]byte(tt.aText)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tHTMLTokenizer.Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // Test_tHTMLTokenizer_Tokenize()
+
+/* _EoF_ */
@@ -0,0 +1,355 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type (
+	// TJournalOp identifies the kind of modification recorded
+	// by a `TJournalEntry`.
+	TJournalOp byte
+
+	// TJournalEntry is a single modification of a `THashList`.
+	//
+	// The entries are meant to be replayed in order on exactly the
+	// data they were recorded for: while e.g. adding an ID twice
+	// doesn't change anything, replaying a merge, rename or delete
+	// on newer data might affect tags added later.
+	TJournalEntry struct {
+		Op  TJournalOp // kind of modification
+		Tag string     // #hashtag/@mention affected (if any)
+		ID  string     // ID affected (if any)
+		Arg string     // operation specific argument (e.g. the new ID)
+	}
+
+	// TJournal is an optional interface a `TStorage` can implement
+	// to record single modifications instead of saving the whole
+	// list after each change.
+	//
+	// The storage's `Load()` method is expected to return the
+	// entries recorded since the last `Save()` in the `Journal`
+	// field of `TStorageData`, and `Save()` is expected to
+	// discard all entries recorded so far. Since the entries
+	// are not idempotent (see `TJournalEntry`) `Load()` must not
+	// return entries already reflected by the data, even if the
+	// storage was interrupted while saving.
+	TJournal interface {
+		// Append adds `aEntries` to the journal returning the
+		// journal's total size (in bytes) and a possible error.
+		Append(aEntries []TJournalEntry) (int, error)
+	}
+)
+
+const (
+	// JournalAdd records adding `ID` to the list of `Tag`.
+	JournalAdd TJournalOp = '+'
+
+	// JournalRemove records removing `ID` from the list of `Tag`.
+	JournalRemove TJournalOp = '-'
+
+	// JournalRename records renaming `ID` to `Arg` in all lists.
+	JournalRename TJournalOp = '>'
+
+	// JournalClear records deleting all #hashtags/@mentions.
+	JournalClear TJournalOp = '!'
+
+	// JournalAlias records making `Tag` an alias of `Arg`
+	// (or removing the alias if `Arg` is empty).
+	JournalAlias TJournalOp = '='
+
+	// JournalMerge records moving all IDs of `Tag` to `Arg`.
+	JournalMerge TJournalOp = '<'
+
+	// JournalDelete records deleting `Tag` and all its IDs.
+	JournalDelete TJournalOp = '~'
+
+	// JournalCount records setting the number of occurrences of
+	// `Tag` in `ID` to `Arg`.
+	JournalCount TJournalOp = '%'
+
+	// JournalSeen records setting the first/last seen times of
+	// `Tag` in `ID` to the time interval `Arg`.
+	JournalSeen TJournalOp = '@'
+)
+
+const (
+	// Suffix appended to a file's name to get its journal's name.
+	journalSuffix = ".journal"
+
+	// Start of a journal file's first line which holds the checksum
+	// of the file the journal's entries refer to.
+	journalHead = "#\t"
+
+	// Default journal size (in bytes) which triggers writing
+	// the whole list.
+	defaultJournalSize = 1 << 20
+)
+
+// String returns the entry as a line of the journal file
+// (without the trailing linefeed).
+//
+// (Implements `Stringer` interface)
+func (je TJournalEntry) String() string {
+	return string(je.Op) + "\t" + strconv.Quote(je.Tag) +
+		"\t" + strconv.Quote(je.ID) + "\t" + strconv.Quote(je.Arg)
+} // String()
+
+// `parseJournalEntry()` returns the entry represented by `aLine`
+// and whether `aLine` is a valid journal line.
+//
+// `aLine` is a line written by `TJournalEntry.String()`.
+func parseJournalEntry(aLine string) (rEntry TJournalEntry, rOK bool) {
+	fields := strings.Split(aLine, "\t")
+	if (4 != len(fields)) || (1 != len(fields[0])) {
+		return
+	}
+	var err error
+	rEntry.Op = TJournalOp(fields[0][0])
+	if rEntry.Tag, err = strconv.Unquote(fields[1]); nil != err {
+		return
+	}
+	if rEntry.ID, err = strconv.Unquote(fields[2]); nil != err {
+		return
+	}
+	if rEntry.Arg, err = strconv.Unquote(fields[3]); nil != err {
+		return
+	}
+
+	return rEntry, true
+} // parseJournalEntry()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `fileSum()` returns the CRC32 checksum of the contents of
+// `aFilename` and a possible error.
+//
+// If the file doesn't exist the result is zero (i.e. the checksum
+// of an empty file).
+//
+// `aFilename` is the name of the file to read.
+func fileSum(aFilename string) (uint32, error) {
+	file, err := openRead(aFilename)
+	if (nil != err) || (nil == file) {
+		return 0, err
+	}
+	defer file.Close()
+
+	hash := crc32.New(crc32.MakeTable(crc32.Castagnoli))
+	if _, err = io.Copy(hash, file); nil != err {
+		return 0, err
+	}
+
+	return hash.Sum32(), nil
+} // fileSum()
+
+// Append adds `aEntries` to the journal file returning the journal's
+// total size (in bytes) and a possible error.
+//
+// If there is an error, it will be of type `*PathError`.
+//
+// (Implements `TJournal` interface)
+func (fs *tFileStorage) Append(aEntries []TJournalEntry) (int, error) {
+	if 0 == len(fs.fn) {
+		return 0, &os.PathError{Op: "open", Path: fs.fn, Err: os.ErrNotExist}
+	}
+	file, err := os.OpenFile(fs.fn+journalSuffix,
+		os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0660) //#nosec G302
+	if nil != err {
+		return 0, err
+	}
+	defer file.Close()
+
+	var sb strings.Builder
+	fi, err := file.Stat()
+	if nil != err {
+		return 0, err
+	}
+	if 0 == fi.Size() {
+		// tie a new journal to the current file's contents:
+		sum, err := fileSum(fs.fn)
+		if nil != err {
+			return 0, err
+		}
+		sb.WriteString(journalHead + strconv.FormatUint(uint64(sum), 16) + "\n")
+	}
+	for _, entry := range aEntries {
+		sb.WriteString(entry.String() + "\n")
+	}
+	if _, err = file.WriteString(sb.String()); nil != err {
+		return 0, err
+	}
+	if err = file.Sync(); nil != err {
+		return 0, err
+	}
+	if fi, err = file.Stat(); nil != err {
+		return 0, err
+	}
+
+	return int(fi.Size()), nil
+} // Append()
+
+// `loadJournal()` reads the journal file returning its entries
+// and a possible error.
+//
+// If the journal doesn't exist that is not considered an error.
+// A journal written for other contents than those of `aFilename`
+// (e.g. because saving the list was interrupted after replacing
+// the file but before removing the journal) is removed without
+// returning its entries. An incomplete or broken tail (e.g. caused
+// by a crash while appending) is cut off, so that later entries
+// are appended to a valid journal.
+//
+// `aFilename` is the name of the file whose data was loaded.
+func (fs *tFileStorage) loadJournal(aFilename string) ([]TJournalEntry, error) {
+	data, err := ioutil.ReadFile(fs.fn + journalSuffix)
+	if nil != err {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var result []TJournalEntry
+	good := 0 // offset behind the last valid line
+	for good < len(data) {
+		end := bytes.IndexByte(data[good:], '\n')
+		if 0 > end {
+			break
+		}
+		line := string(data[good : good+end])
+		if (0 == good) && strings.HasPrefix(line, journalHead) {
+			sum, err := strconv.ParseUint(line[len(journalHead):], 16, 32)
+			if nil != err {
+				break
+			}
+			current, err := fileSum(aFilename)
+			if nil != err {
+				return nil, err
+			}
+			if uint32(sum) != current {
+				return nil, fs.removeJournal()
+			}
+		} else {
+			entry, ok := parseJournalEntry(line)
+			if !ok {
+				break
+			}
+			result = append(result, entry)
+		}
+		good += end + 1
+	}
+	if good < len(data) {
+		if err = os.Truncate(fs.fn+journalSuffix, int64(good)); nil != err {
+			return nil, err
+		}
+	}
+
+	return result, nil
+} // loadJournal()
+
+// `removeJournal()` deletes the journal file.
+//
+// If the journal doesn't exist that is not considered an error.
+func (fs *tFileStorage) removeJournal() error {
+	if err := os.Remove(fs.fn + journalSuffix); (nil != err) && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+} // removeJournal()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `journal()` records `aEntry` to be written by the next `persist()`.
+//
+// Nothing is recorded if the list doesn't have any storage.
+//
+// `aEntry` is the modification to record.
+func (hl *THashList) journal(aEntry TJournalEntry) {
+	// the mutex.Lock is done by the callers
+
+	if (nil == hl.st) && (0 == len(hl.fn)) {
+		return
+	}
+	if JournalClear == aEntry.Op {
+		// all earlier modifications are void
+		hl.µPending = hl.µPending[:0]
+	}
+	hl.µPending = append(hl.µPending, aEntry)
+} // journal()
+
+// `replay()` applies `aEntries` to the list.
+//
+// `aEntries` are the modifications to apply.
+func (hl *THashList) replay(aEntries []TJournalEntry) *THashList {
+	// the mutex.Lock is done by the callers
+
+	for _, entry := range aEntries {
+		switch entry.Op {
+		case JournalAdd:
+			hl.add0(canonical(entry.Tag), entry.ID)
+		case JournalRemove:
+			hl.remove0(canonical(entry.Tag), entry.ID)
+		case JournalRename:
+			hl.renameID(entry.ID, entry.Arg)
+		case JournalClear:
+			hl.clear()
+		case JournalAlias:
+			if 0 == len(entry.Arg) {
+				hl.aliasRemove(canonical(entry.Tag))
+			} else {
+				hl.aliasAdd(canonical(entry.Tag), canonical(entry.Arg))
+			}
+		case JournalMerge:
+			hl.merge(canonical(entry.Tag), canonical(entry.Arg))
+		case JournalDelete:
+			hl.deleteTag(canonical(entry.Tag))
+		case JournalCount:
+			if n, err := strconv.Atoi(entry.Arg); (nil == err) && hl.occ {
+				hl.setCount0(canonical(entry.Tag), entry.ID, n)
+			}
+		case JournalSeen:
+			if seen, ok := parseSeen(entry.Arg); ok && (nil != hl.clk) {
+				hl.setSeen0(canonical(entry.Tag), entry.ID, seen)
+			}
+		}
+	}
+	// the entries are already part of the storage:
+	hl.µPending = nil
+
+	return hl
+} // replay()
+
+// WithJournal returns an option to set the maximal size (in bytes)
+// of the list's journal.
+//
+// If the list's storage supports a journal (as the default file
+// storage does) each modification is appended to that journal
+// instead of writing the whole list again. Once the journal grows
+// beyond `aMaxSize` the whole list is written (which also clears
+// the journal). A value of zero selects the default size of 1 MB.
+// A negative value disables the journal, i.e. the whole list is
+// written after each modification.
+//
+// `aMaxSize` is the journal's maximal size in bytes.
+func WithJournal(aMaxSize int) TOption {
+	return func(aList *THashList) {
+		aList.jmax = aMaxSize
+	}
+} // WithJournal()
+
+/* _EoF_ */
@@ -0,0 +1,158 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_parseJournalEntry(t *testing.T) {
+	je1 := TJournalEntry{JournalAdd, "#hash1", "id_a", ""}
+	je2 := TJournalEntry{JournalRename, "", "id\twith\ttabs", "id\nnew"}
+	tests := []struct {
+		name   string
+		line   string
+		want   TJournalEntry
+		wantOK bool
+	}{
+		// TODO: Add test cases.
+		{" 1", je1.String(), je1, true},
+		{" 2", je2.String(), je2, true},
+		{" 3", `+	"#hash1"	"id_`, TJournalEntry{}, false},
+		{" 4", "", TJournalEntry{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := parseJournalEntry(tt.line)
+			if gotOK != tt.wantOK {
+				t.Errorf("parseJournalEntry() ok = %v, want %v", gotOK, tt.wantOK)
+				return
+			}
+			if gotOK && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJournalEntry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_parseJournalEntry()
+
+func TestTHashList_persist(t *testing.T) {
+	fn := delDB("journal.db")
+	defer delDB(fn)
+	hash1, hash2, hash3 := "#hash1", "#hash2", "@mention3"
+	id1, id2, id3 := "id_c", "id_a", "id_b"
+	hl1, _ := New(fn)
+	hl1.IDparse(id1, []byte("blabla "+hash1+" blabla "+hash2))
+	hl1.Store()
+	hl1.IDparse(id2, []byte("blabla "+hash2+" blabla "+hash3)).
+		IDupdate(id1, []byte("blabla "+hash3)).
+		IDrename(id2, id3).
+		HashRemove(hash2, id3)
+	hl2, _ := New(fn)
+	// a journal of 1 byte is always exceeded:
+	hl3, _ := New(delDB("journal.txt"), WithJournal(1), WithFormat(FormatText))
+	defer delDB(hl3.Filename())
+	hl3.IDparse(id1, []byte("blabla "+hash1))
+	tests := []struct {
+		name        string
+		fn          string
+		want        string
+		wantJournal bool
+	}{
+		// TODO: Add test cases.
+		{" 1", fn, hl1.String(), true},
+		{" 2", hl3.Filename(), hl3.String(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.fn)
+			if nil != err {
+				t.Errorf("New() error = %v", err)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("THashList.persist() = %v, want %v", got, tt.want)
+			}
+			_, err = os.Stat(tt.fn + journalSuffix)
+			if gotJournal := (nil == err); gotJournal != tt.wantJournal {
+				t.Errorf("THashList.persist() journal = %v, want %v", gotJournal, tt.wantJournal)
+			}
+		})
+	}
+	if got := hl2.String(); got != hl1.String() {
+		t.Errorf("THashList.Load() = %v, want %v", got, hl1.String())
+	}
+} // TestTHashList_persist()
+
+func TestTFileStorage_loadJournal(t *testing.T) {
+	fn := delDB("journal.tail.db")
+	defer delDB(fn)
+	hl1, _ := New(fn)
+	hl1.HashAdd("#a", "id_a").HashAdd("#b", "id_b")
+	jn := fn + journalSuffix
+	data, _ := ioutil.ReadFile(jn)
+	// cut the journal inside the last line:
+	if err := os.Truncate(jn, int64(len(data)-5)); nil != err {
+		t.Errorf("os.Truncate() error = %v", err)
+		return
+	}
+	hl2, _ := New(fn)
+	hl2.HashAdd("#c", "id_c").HashAdd("#d", "id_d")
+	hl3, err := New(fn)
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got, want := hl3.String(), "[#a]\nid_a\n[#c]\nid_c\n[#d]\nid_d\n"; got != want {
+		t.Errorf("THashList.Load() = %q, want %q", got, want)
+	}
+} // TestTFileStorage_loadJournal()
+
+func TestTFileStorage_staleJournal(t *testing.T) {
+	fn := delDB("journal.stale.db")
+	defer delDB(fn)
+	hl1, _ := New(fn)
+	hl1.HashAdd("#a", "id_a").
+		MergeTags("#a", "#b").
+		HashAdd("#a", "id_c")
+	jn := fn + journalSuffix
+	journal, _ := ioutil.ReadFile(jn)
+	if _, err := hl1.Store(); nil != err {
+		t.Errorf("THashList.Store() error = %v", err)
+		return
+	}
+	// simulate a crash between replacing the file and removing
+	// the journal:
+	if err := ioutil.WriteFile(jn, journal, 0660); nil != err {
+		t.Errorf("ioutil.WriteFile() error = %v", err)
+		return
+	}
+	hl2, err := New(fn)
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got, want := hl2.String(), hl1.String(); got != want {
+		t.Errorf("THashList.Load() = %q, want %q", got, want)
+	}
+	if _, err = os.Stat(jn); !os.IsNotExist(err) {
+		t.Errorf("THashList.Load() journal error = %v, want %v", err, os.ErrNotExist)
+	}
+	// a journal without checksum (written by earlier versions):
+	legacy := TJournalEntry{Op: JournalAdd, Tag: "#d", ID: "id_d"}
+	if err = ioutil.WriteFile(jn, []byte(legacy.String()+"\n"), 0660); nil != err {
+		t.Errorf("ioutil.WriteFile() error = %v", err)
+		return
+	}
+	if hl2, _ = New(fn); 1 != hl2.HashLen("#d") {
+		t.Errorf("THashList.HashLen() = %v, want %v", hl2.HashLen("#d"), 1)
+	}
+} // TestTFileStorage_staleJournal()
+
+/* _EoF_ */
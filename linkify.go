@@ -0,0 +1,86 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+)
+
+// TLinkFunc returns the text to replace `aMatch` by (e.g. a HTML
+// or Markdown link).
+//
+// @see Linkify()
+type TLinkFunc func(aMatch TMatch) string
+
+// LinkTemplate returns a `TLinkFunc` replacing the placeholders
+// in `aTemplate` by the respective values of a match:
+//
+//	{tag}   the #hashtag/@mention as spelled in the text,
+//	{index} the list index (i.e. the canonical form) of the tag,
+//	{name}  the list index without its leading sigil (escaped
+//	        to be used as part of an URL path).
+//
+// The values are inserted as is, i.e. it's up to the caller to
+// use a template suitable for the text's markup. For example:
+//
+//	LinkTemplate(`<a href="/tags/{name}">{tag}</a>`)
+//	LinkTemplate(`[{tag}](/tags/{name})`)
+//
+// `aTemplate` is the replacement text containing placeholders.
+func LinkTemplate(aTemplate string) TLinkFunc {
+	return func(aMatch TMatch) string {
+		index := aMatch.Index
+		if 0 == len(index) {
+			index = mapIndex(aMatch.Tag[0], aMatch.Tag)
+		}
+		return strings.NewReplacer(
+			"{tag}", aMatch.Tag,
+			"{index}", index,
+			"{name}", url.PathEscape(index[1:]),
+		).Replace(aTemplate)
+	}
+} // LinkTemplate()
+
+// Linkify returns `aText` with all #hashtags and @mentions replaced
+// by the text returned by the `TLinkFunc` of their respective first
+// character (i.e. the tag's sigil like '#' or '@').
+//
+// The tags are found exactly like `IDparse()` would do (see
+// `Matches()`), so rendering the text and indexing it always agree.
+// Tags whose first character has no entry in `aLinks` are left
+// unchanged.
+//
+// `aText` is the text to process.
+//
+// `aLinks` maps the tags' first character to the function providing
+// the replacement text.
+func (hl *THashList) Linkify(aText []byte, aLinks map[byte]TLinkFunc) []byte {
+	var buf bytes.Buffer
+	pos := 0
+	for _, match := range hl.Matches(aText) {
+		link, ok := aLinks[match.Tag[0]]
+		if !ok || (nil == link) || (match.Offset < pos) {
+			continue
+		}
+		buf.Write(aText[pos:match.Offset])
+		buf.WriteString(link(match))
+		pos = match.Offset + match.Length
+	}
+	if 0 == pos {
+		// nothing replaced
+		return append([]byte(nil), aText...)
+	}
+	buf.Write(aText[pos:])
+
+	return buf.Bytes()
+} // Linkify()
+
+/* _EoF_ */
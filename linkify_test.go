@@ -0,0 +1,61 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTHashList_Linkify(t *testing.T) {
+	hlText, _ := New("")
+	hlMD, _ := New("", WithTokenizer(NewMarkdownTokenizer()))
+	hlHTML, _ := New("", WithTokenizer(NewHTMLTokenizer()))
+	html := map[byte]TLinkFunc{
+		'#': LinkTemplate(`<a href="/tags/{name}">{tag}</a>`),
+		'@': LinkTemplate(`<a href="/users/{name}">{tag}</a>`),
+	}
+	md := map[byte]TLinkFunc{
+		'#': LinkTemplate(`[{tag}](/tags/{name})`),
+	}
+	upper := map[byte]TLinkFunc{
+		'@': func(aMatch TMatch) string {
+			return strings.ToUpper(aMatch.Tag)
+		},
+	}
+	tests := []struct {
+		name   string
+		hl     *THashList
+		aText  string
+		aLinks map[byte]TLinkFunc
+		want   string
+	}{
+		// TODO: Add test cases.
+		{" 0", hlText, "no tags", html, "no tags"},
+		{" 1", hlText, "#Go by @Bob.", html,
+			`<a href="/tags/go">#Go</a> by <a href="/users/bob">@Bob</a>.`},
+		{" 2", hlText, "#Straße #C++", html,
+			`<a href="/tags/strasse">#Straße</a> <a href="/tags/c">#C</a>++`},
+		{" 3", hlText, "#go @bob", md, "[#go](/tags/go) @bob"},
+		{" 4", hlText, "mail bob@example.com, #fff or http://x.org/#a", html,
+			"mail bob@example.com, #fff or http://x.org/#a"},
+		{" 5", hlMD, "`#code` and #tag", md, "`#code` and [#tag](/tags/tag)"},
+		{" 6", hlHTML, `<a href="#top">#caf&eacute;</a>`, html,
+			`<a href="#top"><a href="/tags/caf%C3%A9">#café</a></a>`},
+		{" 7", hlText, "@alice and @bob", upper, "@ALICE and @BOB"},
+		{" 8", hlText, "#go", nil, "#go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(tt.hl.Linkify([]byte(tt.aText), tt.aLinks)); got != tt.want {
+				t.Errorf("THashList.Linkify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_Linkify()
+
+/* _EoF_ */
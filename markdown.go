@@ -0,0 +1,266 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"regexp"
+)
+
+type (
+	// `tMarkdownTokenizer` is a tokenizer handling Markdown texts.
+	tMarkdownTokenizer struct {
+		sigils []TSigil // kinds of tags to find (nil: the default)
+	}
+)
+
+var (
+	// match: start of a list item
+	mdListRE = regexp.MustCompile(`^ {0,3}(?:[*+-]|[0-9]{1,9}[.)])(?:[ \t]|$)`)
+
+	// match: link reference definition (`[label]: URL "title"`)
+	mdRefDefRE = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:`)
+
+	// match: autolink (`<scheme:…>` or `<user@host>`)
+	mdAutolinkRE = regexp.MustCompile(`^<(?:[A-Za-z][A-Za-z0-9+.-]{1,31}:[^\s<>]*|[^\s<>@]+@[^\s<>]+)>`)
+)
+
+// `mdBlank()` replaces all bytes in `aText[aStart:aEnd]` by spaces
+// (keeping linefeeds) so that the positions of the remaining text
+// are preserved.
+func mdBlank(aText []byte, aStart, aEnd int) {
+	if aEnd > len(aText) {
+		aEnd = len(aText)
+	}
+	for idx := aStart; idx < aEnd; idx++ {
+		if '\n' != aText[idx] {
+			aText[idx] = ' '
+		}
+	}
+} // mdBlank()
+
+// `mdFence()` returns the fence character and length if `aLine`
+// starts a fenced code block or zero otherwise.
+//
+// `aLine` is the line to check.
+func mdFence(aLine []byte) (rChar byte, rLen int) {
+	line := bytes.TrimLeft(aLine, " ")
+	if (3 < len(aLine)-len(line)) || (3 > len(line)) {
+		return
+	}
+	if ('`' != line[0]) && ('~' != line[0]) {
+		return
+	}
+	for rLen < len(line) && line[rLen] == line[0] {
+		rLen++
+	}
+	if 3 > rLen {
+		return 0, 0
+	}
+	if ('`' == line[0]) && (0 <= bytes.IndexByte(line[rLen:], '`')) {
+		// backticks are not allowed in the info string
+		return 0, 0
+	}
+
+	return line[0], rLen
+} // mdFence()
+
+// `mdIndented()` returns whether `aLine` is indented by at least
+// four columns.
+func mdIndented(aLine []byte) bool {
+	return bytes.HasPrefix(aLine, []byte("    ")) ||
+		bytes.HasPrefix(aLine, []byte("\t")) ||
+		bytes.HasPrefix(aLine, []byte(" \t")) ||
+		bytes.HasPrefix(aLine, []byte("  \t")) ||
+		bytes.HasPrefix(aLine, []byte("   \t"))
+} // mdIndented()
+
+// `mdBlocks()` blanks fenced and indented code blocks as well as
+// the targets of link reference definitions.
+//
+// Indented lines following a list item are considered part of that
+// item (i.e. they are not treated as code).
+//
+// `aText` is the Markdown text to process (modified in place).
+func mdBlocks(aText []byte) {
+	var (
+		fenceChar byte // character of the current code fence
+		fenceLen  int  // length of the current code fence
+		inCode    bool // inside an indented code block
+		inList    bool // inside a list item
+		prevBlank = true
+	)
+	for start := 0; start < len(aText); {
+		end := bytes.IndexByte(aText[start:], '\n')
+		if 0 > end {
+			end = len(aText)
+		} else {
+			end += start
+		}
+		line := aText[start:end]
+		blank := 0 == len(bytes.TrimSpace(line))
+
+		switch {
+		case 0 < fenceLen:
+			if c, l := mdFence(line); (c == fenceChar) && (l >= fenceLen) &&
+				(0 == len(bytes.Trim(bytes.TrimLeft(line, " "), string(c)+" \t"))) {
+				fenceLen = 0 // closing fence
+			}
+			mdBlank(aText, start, end)
+
+		case blank:
+			// blank lines don't end an indented code block
+
+		case mdIndented(line) && (inCode || (prevBlank && !inList)):
+			inCode = true
+			mdBlank(aText, start, end)
+
+		default:
+			inCode = false
+			if fenceChar, fenceLen = mdFence(line); 0 < fenceLen {
+				inList = false
+				mdBlank(aText, start, end)
+				break
+			}
+			if mdListRE.Match(line) {
+				inList = true
+			} else if !mdIndented(line) && prevBlank {
+				inList = false
+			}
+			if loc := mdRefDefRE.FindIndex(line); nil != loc {
+				mdBlank(aText, start+loc[1], end)
+			}
+		}
+		prevBlank = blank
+		start = end + 1
+	}
+} // mdBlocks()
+
+// `mdInline()` blanks code spans, link targets, autolinks and
+// HTML comments.
+//
+// `aText` is the Markdown text to process (modified in place).
+func mdInline(aText []byte) {
+	for idx := 0; idx < len(aText); {
+		switch aText[idx] {
+		case '\\':
+			// escaped character
+			idx += 2
+			continue
+
+		case '`':
+			n := 1
+			for (idx+n < len(aText)) && ('`' == aText[idx+n]) {
+				n++
+			}
+			// look for a closing run of exactly the same length:
+			for pos := idx + n; pos < len(aText); {
+				if '`' != aText[pos] {
+					pos++
+					continue
+				}
+				m := 1
+				for (pos+m < len(aText)) && ('`' == aText[pos+m]) {
+					m++
+				}
+				if m == n {
+					mdBlank(aText, idx, pos+m)
+					idx = pos
+					break
+				}
+				pos += m
+			}
+			idx += n
+			continue
+
+		case '<':
+			if bytes.HasPrefix(aText[idx:], []byte("<!--")) {
+				end := bytes.Index(aText[idx+4:], []byte("-->"))
+				if 0 > end {
+					end = len(aText)
+				} else {
+					end += idx + 4 + 3
+				}
+				mdBlank(aText, idx, end)
+				idx = end
+				continue
+			}
+			if loc := mdAutolinkRE.FindIndex(aText[idx:]); nil != loc {
+				mdBlank(aText, idx, idx+loc[1])
+				idx += loc[1]
+				continue
+			}
+
+		case ']':
+			if (idx+1 < len(aText)) && ('(' == aText[idx+1]) {
+				// inline link/image: blank the destination (and title)
+				depth, end := 0, idx+1
+				for ; end < len(aText); end++ {
+					if '\\' == aText[end] {
+						end++
+						continue
+					}
+					if '(' == aText[end] {
+						depth++
+					} else if ')' == aText[end] {
+						if depth--; 0 == depth {
+							break
+						}
+					}
+				}
+				if end < len(aText) {
+					mdBlank(aText, idx+1, end+1)
+					idx = end + 1
+					continue
+				}
+			}
+		}
+		idx++
+	}
+} // mdInline()
+
+// Match returns the #hashtags and @mentions found in `aText`
+// along with their positions ignoring those in code spans, code
+// blocks, link targets and HTML comments.
+//
+// (Implements `TMatcher` interface)
+func (mt tMarkdownTokenizer) Match(aText []byte) []TMatch {
+	// blanking keeps the positions of the remaining text intact
+	text := make([]byte, len(aText))
+	copy(text, aText)
+	mdBlocks(text)
+	mdInline(text)
+
+	return setRuneOffsets(aText, textMatches(text, sigilsOf(mt.sigils)))
+} // Match()
+
+// Tokenize returns the #hashtags and @mentions found in `aText`
+// ignoring those in code spans, code blocks, link targets and
+// HTML comments.
+//
+// (Implements `TTokenizer` interface)
+func (mt tMarkdownTokenizer) Tokenize(aText []byte) []string {
+	return matchTags(mt.Match(aText))
+} // Tokenize()
+
+// `withSigils()` returns a copy of the tokenizer using `aSigils`.
+func (mt tMarkdownTokenizer) withSigils(aSigils []TSigil) TTokenizer {
+	return tMarkdownTokenizer{sigils: aSigils}
+} // withSigils()
+
+// NewMarkdownTokenizer returns a tokenizer handling Markdown texts.
+//
+// It finds #hashtags and @mentions in normal prose, headings and
+// lists but ignores inline code, fenced and indented code blocks,
+// the targets of links and images, autolinks and HTML comments.
+func NewMarkdownTokenizer() TTokenizer {
+	return tMarkdownTokenizer{}
+} // NewMarkdownTokenizer()
+
+/* _EoF_ */
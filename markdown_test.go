@@ -0,0 +1,47 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_tMarkdownTokenizer_Tokenize(t *testing.T) {
+	tok := NewMarkdownTokenizer()
+	tests := []struct {
+		name  string
+		aText string
+		want  []string
+	}{
+		// TODO: Add test cases.
+		{"prose", "Some #prose with @bob.", []string{"#prose", "@bob"}},
+		{"heading", "## About #golang\n\ntext", []string{"#golang"}},
+		{"list", "* item #one\n* item #two\n\n    continued #three", []string{"#one", "#two", "#three"}},
+		{"code span", "Use `#include <stdio.h>` in #clang", []string{"#clang"}},
+		{"double code span", "``a ` #no`` but #yes", []string{"#yes"}},
+		{"unclosed span", "a `#tag", []string{"#tag"}},
+		{"fenced", "#before\n```c\n#include <stdio.h>\n```\n#after", []string{"#before", "#after"}},
+		{"tilde fence", "~~~~\n#no\n~~~\n#still\n~~~~\n#yes", []string{"#yes"}},
+		{"indented", "text #one\n\n    # shell comment\n    echo #two\n\n#three", []string{"#one", "#three"}},
+		{"link", "[see #this](page.html#anchor) and ![img](a#b.png)", []string{"#this"}},
+		{"link parens", "[x](http://x.org/a_(b)#c) #tag", []string{"#tag"}},
+		{"refdef", "[ref]: http://example.com/#anchor\n#tag", []string{"#tag"}},
+		{"autolink", "<http://example.com/#anchor> <mail@example.com> #tag", []string{"#tag"}},
+		{"comment", "<!-- #hidden\n@hidden -->#visible", []string{"#visible"}},
+		{"escaped", "\\`#tag\\`", []string{"#tag"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Tokenize([]byte(tt.aText)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tMarkdownTokenizer.Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // Test_tMarkdownTokenizer_Tokenize()
+
+/* _EoF_ */
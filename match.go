@@ -0,0 +1,138 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+type (
+	// TMatch is a single #hashtag/@mention found in a text.
+	TMatch struct {
+		// Tag is the #hashtag/@mention as spelled in the text
+		// (i.e. not lower-cased).
+		Tag string
+
+		// Index is the list index of `Tag` (i.e. its canonical form
+		// as used by e.g. `HashList()`); it's set by `Matches()`.
+		Index string
+
+		// Offset is the byte offset of `Tag` in the text.
+		Offset int
+
+		// RuneOffset is the offset of `Tag` in the text counted
+		// in runes (i.e. Unicode code points).
+		RuneOffset int
+
+		// Length is the number of bytes `Tag` occupies in the text.
+		//
+		// With HTML documents that length might differ from
+		// `len(Tag)` if the tag contains character entities.
+		Length int
+	}
+
+	// TMatcher is an optional interface a `TTokenizer` can implement
+	// to report the positions of the #hashtags and @mentions found.
+	//
+	// All tokenizers provided by this package implement it.
+	TMatcher interface {
+		// Match returns the #hashtags and @mentions found in `aText`
+		// ordered by their position.
+		Match(aText []byte) []TMatch
+	}
+)
+
+// `matchTags()` returns the tags of `aList`.
+func matchTags(aList []TMatch) (rList []string) {
+	for _, match := range aList {
+		rList = append(rList, match.Tag)
+	}
+
+	return
+} // matchTags()
+
+// `setRuneOffsets()` computes the `RuneOffset` of all `aList` entries
+// returning the list.
+//
+// `aText` is the text the matches were found in.
+//
+// `aList` must be ordered by `Offset`.
+func setRuneOffsets(aText []byte, aList []TMatch) []TMatch {
+	pos, runes := 0, 0
+	for idx := range aList {
+		runes += utf8.RuneCount(aText[pos:aList[idx].Offset])
+		pos = aList[idx].Offset
+		aList[idx].RuneOffset = runes
+	}
+
+	return aList
+} // setRuneOffsets()
+
+// `tokenMatches()` returns the positions of the words returned by
+// `aTokenizer` which doesn't implement the `TMatcher` interface.
+//
+// The words are searched in `aText` one after the other; words not
+// found verbatim (e.g. because the tokenizer modified them) are
+// ignored.
+func tokenMatches(aTokenizer TTokenizer, aText []byte) (rList []TMatch) {
+	pos := 0
+	for _, word := range aTokenizer.Tokenize(aText) {
+		idx := bytes.Index(aText[pos:], []byte(word))
+		if 0 > idx {
+			continue
+		}
+		rList = append(rList, TMatch{
+			Tag:    word,
+			Offset: pos + idx,
+			Length: len(word),
+		})
+		pos += idx + len(word)
+	}
+
+	return setRuneOffsets(aText, rList)
+} // tokenMatches()
+
+// Matches returns all #hashtags and @mentions found in `aText`
+// along with their positions.
+//
+// The text is analysed by the list's tokenizer (see `WithTokenizer()`)
+// just like `IDparse()` does, but the list itself is not changed.
+// Tags rejected by the list's filter (see `WithFilter()`) are skipped
+// without calling the filter's `OnReject` function.
+// The result is ordered by the matches' position in `aText`.
+//
+// `aText` is the text to search.
+func (hl *THashList) Matches(aText []byte) (rList []TMatch) {
+	var matches []TMatch
+	tok := hl.tokenizer()
+	if matcher, ok := tok.(TMatcher); ok {
+		matches = matcher.Match(aText)
+	} else {
+		matches = tokenMatches(tok, aText)
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	for _, match := range matches {
+		if (1 < len(match.Tag)) && hl.isSigil(match.Tag[0]) {
+			mapIdx := mapIndex(match.Tag[0], match.Tag)
+			if hl.rejects(mapIdx) {
+				// `IDparse()` wouldn't index it either
+				continue
+			}
+			match.Index = hl.alias(mapIdx)
+			rList = append(rList, match)
+		}
+	}
+
+	return
+} // Matches()
+
+/* _EoF_ */
@@ -0,0 +1,68 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTHashList_Matches(t *testing.T) {
+	hlText, _ := New("")
+	hlMD, _ := New("", WithTokenizer(NewMarkdownTokenizer()))
+	hlHTML, _ := New("", WithTokenizer(NewHTMLTokenizer()))
+	hlFunc, _ := New("", WithTokenizer(TTokenizerFunc(func(aText []byte) []string {
+		return strings.Fields(string(aText))
+	})))
+	hlUpper, _ := New("", WithTokenizer(TTokenizerFunc(func(aText []byte) []string {
+		// modified words can't be found in the text
+		return strings.Fields(strings.ToUpper(string(aText)))
+	})))
+	tests := []struct {
+		name  string
+		hl    *THashList
+		aText string
+		want  []TMatch
+	}{
+		// TODO: Add test cases.
+		{" 0", hlText, "nothing here", nil},
+		{" 1", hlText, "Go #Golang @Bob", []TMatch{
+			{"#Golang", "#golang", 3, 3, 7},
+			{"@Bob", "@bob", 11, 11, 4},
+		}},
+		{" 2", hlText, "Über #Straße und #日本語", []TMatch{
+			{"#Straße", "#strasse", 6, 5, 8},
+			{"#日本語", "#日本語", 19, 17, 10},
+		}},
+		{" 3", hlMD, "`#no` #yes", []TMatch{
+			{"#yes", "#yes", 6, 6, 4},
+		}},
+		{" 4", hlHTML, `<p class="x">#caf&eacute; &amp; #Tag</p>`, []TMatch{
+			{"#café", "#café", 13, 13, 12},
+			{"#Tag", "#tag", 32, 32, 4},
+		}},
+		{" 5", hlHTML, "<b>ä</b> #one", []TMatch{
+			{"#one", "#one", 10, 9, 4},
+		}},
+		{" 6", hlFunc, "#a @b c #a", []TMatch{
+			{"#a", "#a", 0, 0, 2},
+			{"@b", "@b", 3, 3, 2},
+			{"#a", "#a", 8, 8, 2},
+		}},
+		{" 7", hlUpper, "#a @b c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hl.Matches([]byte(tt.aText)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_Matches()
+
+/* _EoF_ */
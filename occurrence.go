@@ -0,0 +1,281 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type (
+	// TIDCount holds an ID and the number of occurrences of a
+	// #hashtag/@mention in the text identified by that ID.
+	//
+	// @see TagCounts(), TagRanked()
+	TIDCount struct {
+		ID    string  // the ID associated with the #hashtag/@mention
+		Count int     // number of occurrences in the ID's text
+		Score float64 // relevance of the ID (set by `TagRanked()`)
+	}
+
+	// `tOccurrences` maps list indices to IDs with their number of
+	// occurrences; IDs occurring just once are not stored.
+	tOccurrences map[string]map[string]int
+)
+
+// `count()` returns the number of occurrences of `aMapIdx` in `aID`
+// (i.e. zero if `aID` is not associated with `aMapIdx`).
+//
+// `aMapIdx` is the (canonical) list index to lookup.
+//
+// `aID` is the ID to lookup.
+func (hl *THashList) count(aMapIdx, aID string) int {
+	// the mutex.Lock is done by the callers
+
+	if n, ok := hl.oc[aMapIdx][aID]; ok {
+		return n
+	}
+	if sl, ok := hl.hl[aMapIdx]; ok && (0 <= sl.indexOf(aID)) {
+		return 1
+	}
+
+	return 0
+} // count()
+
+// `moveCount()` transfers the number of occurrences of `aFromIdx`
+// in `aFromID` to `aToIdx` in `aToID` adding the latter's count.
+//
+// This method must be called before the ID itself is moved.
+func (hl *THashList) moveCount(aFromIdx, aFromID, aToIdx, aToID string) {
+	// the mutex.Lock is done by the callers
+
+	if !hl.occ {
+		return
+	}
+	n := hl.count(aFromIdx, aFromID) + hl.count(aToIdx, aToID)
+	hl.setCount0(aFromIdx, aFromID, 1)
+	hl.setCount0(aToIdx, aToID, n)
+} // moveCount()
+
+// `setCount()` sets the number of occurrences of `aMapIdx` in `aID`
+// to `aCount` recording the change in the list's journal.
+func (hl *THashList) setCount(aMapIdx, aID string, aCount int) {
+	// the mutex.Lock is done by the callers
+
+	if hl.count(aMapIdx, aID) == aCount {
+		return
+	}
+	hl.setCount0(aMapIdx, aID, aCount)
+	atomic.StoreUint32(&hl.µChange, 0)
+	hl.journal(TJournalEntry{Op: JournalCount, Tag: aMapIdx, ID: aID,
+		Arg: strconv.Itoa(aCount)})
+} // setCount()
+
+// `setCount0()` sets the number of occurrences of `aMapIdx` in `aID`
+// to `aCount`; counts below two are not stored.
+func (hl *THashList) setCount0(aMapIdx, aID string, aCount int) {
+	// the mutex.Lock is done by the callers
+
+	if 1 >= aCount {
+		if ids, ok := hl.oc[aMapIdx]; ok {
+			delete(ids, aID)
+			if 0 == len(ids) {
+				delete(hl.oc, aMapIdx)
+			}
+		}
+		return
+	}
+	if nil == hl.oc {
+		hl.oc = make(tOccurrences)
+	}
+	ids, ok := hl.oc[aMapIdx]
+	if !ok {
+		ids = make(map[string]int)
+		hl.oc[aMapIdx] = ids
+	}
+	ids[aID] = aCount
+} // setCount0()
+
+// `setCounts()` sets the number of occurrences of all `aTags`
+// in `aID` if the list counts occurrences (see `WithOccurrences()`).
+//
+// `aID` is the ID whose text was parsed.
+//
+// `aTags` are the list indices found in the ID's text (including
+// duplicates).
+func (hl *THashList) setCounts(aID string, aTags []string) {
+	// the mutex.Lock is done by the callers
+
+	if !hl.occ {
+		return
+	}
+	counts := make(map[string]int, len(aTags))
+	for _, mapIdx := range aTags {
+		counts[mapIdx]++
+	}
+	for mapIdx, n := range counts {
+		hl.setCount(mapIdx, aID, n)
+	}
+} // setCounts()
+
+// `idLines()` returns `aIDs` as a linefeed separated string where
+// IDs with more than one occurrence are followed by a tab and their
+// count, and IDs with timestamps by a tab and the time interval.
+func idLines(aIDs []string, aCounts map[string]int, aSeen map[string]TSeen) string {
+	if (0 == len(aCounts)) && (0 == len(aSeen)) {
+		return strings.Join(aIDs, "\n")
+	}
+	lines := make([]string, len(aIDs))
+	for idx, id := range aIDs {
+		lines[idx] = id
+		if n, ok := aCounts[id]; ok {
+			lines[idx] += "\t" + strconv.Itoa(n)
+		}
+		if seen, ok := aSeen[id]; ok {
+			lines[idx] += "\t" + seen.String()
+		}
+	}
+
+	return strings.Join(lines, "\n")
+} // idLines()
+
+// `parseIDLine()` returns the ID, count and timestamps of a line
+// written by `idLines()`.
+func parseIDLine(aLine string) (rID string, rCount int, rSeen TSeen) {
+	rID, rCount = aLine, 1
+	if idx := strings.LastIndexByte(rID, '\t'); 0 < idx {
+		if seen, ok := parseSeen(rID[idx+1:]); ok {
+			rID, rSeen = strings.TrimSpace(rID[:idx]), seen
+		}
+	}
+	if idx := strings.LastIndexByte(rID, '\t'); 0 < idx {
+		if n, err := strconv.Atoi(rID[idx+1:]); (nil == err) && (1 < n) {
+			rID, rCount = strings.TrimSpace(rID[:idx]), n
+		}
+	}
+
+	return
+} // parseIDLine()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// TagCount returns the number of occurrences of `aTag` in the text
+// identified by `aID` (zero if `aID` is not associated with `aTag`).
+//
+// Without counting occurrences (see `WithOccurrences()`) the result
+// is either zero or one.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+//
+// `aID` is the ID to lookup.
+func (hl *THashList) TagCount(aSigil byte, aTag, aID string) int {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return 0
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	return hl.count(hl.alias(mapIndex(aSigil, aTag)), aID)
+} // TagCount()
+
+// TagCounts returns the IDs associated with `aTag` along with the
+// number of the tag's occurrences in each ID's text.
+//
+// The result is sorted by ID.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+func (hl *THashList) TagCounts(aSigil byte, aTag string) (rList []TIDCount) {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	mapIdx := hl.alias(mapIndex(aSigil, aTag))
+	if sl, ok := hl.hl[mapIdx]; ok {
+		rList = make([]TIDCount, len(*sl))
+		for idx, id := range *sl {
+			rList[idx] = TIDCount{ID: id, Count: hl.count(mapIdx, id)}
+		}
+	}
+
+	return
+} // TagCounts()
+
+// TagRanked returns the IDs associated with `aTag` ordered by their
+// relevance (highest first).
+//
+// The relevance (`Score`) is computed TF-IDF style: the number of the
+// tag's occurrences in an ID's text relative to the total number of
+// tags in that text, weighted by the tag's rarity in the whole list.
+// IDs with the same score are sorted by ID.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+func (hl *THashList) TagRanked(aSigil byte, aTag string) (rList []TIDCount) {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	mapIdx := hl.alias(mapIndex(aSigil, aTag))
+	sl, ok := hl.hl[mapIdx]
+	if !ok {
+		return
+	}
+	ix := hl.index()
+	idf := math.Log(1 + float64(len(ix))/float64(len(*sl)))
+	rList = make([]TIDCount, len(*sl))
+	for idx, id := range *sl {
+		total := 0
+		if tags, ok := ix[id]; ok {
+			for _, tag := range *tags {
+				total += hl.count(tag, id)
+			}
+		}
+		n := hl.count(mapIdx, id)
+		rList[idx] = TIDCount{
+			ID:    id,
+			Count: n,
+			Score: float64(n) / float64(total) * idf,
+		}
+	}
+	sort.SliceStable(rList, func(i, j int) bool {
+		return rList[i].Score > rList[j].Score
+	})
+
+	return
+} // TagRanked()
+
+// WithOccurrences returns an option to record how often each
+// #hashtag/@mention occurs in the text of an ID.
+//
+// The counts are set by `IDparse()` and `IDupdate()` (replacing
+// the counts of an earlier call for the same ID) while e.g.
+// `HashAdd()` counts a single occurrence. They are available by
+// `TagCount()`, `TagCounts()` and `TagRanked()`, `CountedList()`
+// sums them up, and they are stored along with the list.
+//
+// `aCount` tells whether to count the occurrences.
+func WithOccurrences(aCount bool) TOption {
+	return func(aList *THashList) {
+		aList.occ = aCount
+	}
+} // WithOccurrences()
+
+/* _EoF_ */
@@ -0,0 +1,268 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_parseIDLine(t *testing.T) {
+	t1 := time.Date(2026, 10, 18, 9, 30, 0, 0, time.UTC)
+	t2 := t1.Add(90 * time.Minute)
+	seen := TSeen{t1, t2}
+	tests := []struct {
+		name      string
+		aLine     string
+		wantID    string
+		wantCount int
+		wantSeen  TSeen
+	}{
+		{" 1", "id_a", "id_a", 1, TSeen{}},
+		{" 2", "id_a\t3", "id_a", 3, TSeen{}},
+		{" 3", "id_a\t1", "id_a\t1", 1, TSeen{}},
+		{" 4", "id a\tx", "id a\tx", 1, TSeen{}},
+		{" 5", "\t3", "\t3", 1, TSeen{}},
+		{" 6", "id_a\t" + seen.String(), "id_a", 1, seen},
+		{" 7", "id_a\t3\t" + seen.String(), "id_a", 3, seen},
+		{" 8", "id_a\t2026-10-18/x", "id_a\t2026-10-18/x", 1, TSeen{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID, gotCount, gotSeen := parseIDLine(tt.aLine)
+			if gotID != tt.wantID {
+				t.Errorf("parseIDLine() ID = %q, want %q", gotID, tt.wantID)
+			}
+			if gotCount != tt.wantCount {
+				t.Errorf("parseIDLine() Count = %v, want %v", gotCount, tt.wantCount)
+			}
+			if gotSeen != tt.wantSeen {
+				t.Errorf("parseIDLine() Seen = %v, want %v", gotSeen, tt.wantSeen)
+			}
+		})
+	}
+} // Test_parseIDLine()
+
+func TestTHashList_TagCount(t *testing.T) {
+	hl, _ := New("", WithOccurrences(true))
+	hl.IDparse("id_a", []byte("#go, #Go and #GO @bob")).
+		IDparse("id_b", []byte("#go #rust")).
+		IDparse("id_c", []byte("#rust #rust @bob"))
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		aID    string
+		want   int
+	}{
+		{" 1", '#', "go", "id_a", 3},
+		{" 2", '#', "#GO", "id_b", 1},
+		{" 3", '#', "go", "id_c", 0},
+		{" 4", '#', "rust", "id_c", 2},
+		{" 5", '@', "bob", "id_c", 1},
+		{" 6", '#', "unknown", "id_a", 0},
+		{" 7", '!', "go", "id_a", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagCount(tt.aSigil, tt.aTag, tt.aID); got != tt.want {
+				t.Errorf("THashList.TagCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_TagCount()
+
+func TestTHashList_TagCounts(t *testing.T) {
+	hl, _ := New("", WithOccurrences(true))
+	hl.IDparse("id_a", []byte("#go, #Go and #GO @bob")).
+		IDparse("id_b", []byte("#go #rust")).
+		IDparse("id_c", []byte("#rust #rust @bob"))
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		want   []TIDCount
+	}{
+		{" 1", '#', "go", []TIDCount{{"id_a", 3, 0}, {"id_b", 1, 0}}},
+		{" 2", '#', "rust", []TIDCount{{"id_b", 1, 0}, {"id_c", 2, 0}}},
+		{" 3", '@', "bob", []TIDCount{{"id_a", 1, 0}, {"id_c", 1, 0}}},
+		{" 4", '#', "unknown", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagCounts(tt.aSigil, tt.aTag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	want := []TCountItem{{2, "@bob"}, {4, "#go"}, {3, "#rust"}}
+	if got := hl.CountedList(); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.CountedList() = %v, want %v", got, want)
+	}
+} // TestTHashList_TagCounts()
+
+func TestTHashList_TagRanked(t *testing.T) {
+	hl, _ := New("", WithOccurrences(true))
+	hl.IDparse("id_a", []byte("#go, #Go and #GO @bob")).
+		IDparse("id_b", []byte("#go #rust")).
+		IDparse("id_c", []byte("#rust #rust @bob"))
+	hl.IDparse("id_d", []byte("#rust"))
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		want   []string
+	}{
+		{" 1", '#', "go", []string{"id_a", "id_b"}},
+		{" 2", '#', "rust", []string{"id_d", "id_c", "id_b"}},
+		{" 3", '@', "bob", []string{"id_c", "id_a"}},
+		{" 4", '#', "unknown", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ic := range hl.TagRanked(tt.aSigil, tt.aTag) {
+				got = append(got, ic.ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagRanked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	// in `id_b` the rarer `#go` outweighs `#rust`:
+	rare, frequent := hl.TagRanked('#', "go"), hl.TagRanked('#', "rust")
+	if !(rare[1].Score > frequent[2].Score) {
+		t.Errorf("THashList.TagRanked() = %v, want less than %v", frequent[2].Score, rare[1].Score)
+	}
+} // TestTHashList_TagRanked()
+
+func TestTHashList_occurrenceChanges(t *testing.T) {
+	hl, _ := New("", WithOccurrences(true))
+	hl.IDparse("id_a", []byte("#go, #Go and #GO @bob")).
+		IDparse("id_b", []byte("#go #rust")).
+		IDparse("id_c", []byte("#rust #rust @bob"))
+	tests := []struct {
+		name string
+		fn   func()
+		want []TIDCount
+	}{
+		{" 1", func() { hl.IDupdate("id_a", []byte("#go #go @bob")) },
+			[]TIDCount{{"id_a", 2, 0}, {"id_b", 1, 0}}},
+		{" 2", func() { hl.IDrename("id_a", "id_x") },
+			[]TIDCount{{"id_b", 1, 0}, {"id_x", 2, 0}}},
+		{" 3", func() { hl.MergeTags("#rust", "#go") },
+			[]TIDCount{{"id_b", 2, 0}, {"id_c", 2, 0}, {"id_x", 2, 0}}},
+		{" 4", func() { hl.AliasAdd("@bob", "#go") },
+			[]TIDCount{{"id_b", 2, 0}, {"id_c", 3, 0}, {"id_x", 3, 0}}},
+		{" 5", func() { hl.HashRemove("#go", "id_x") },
+			[]TIDCount{{"id_b", 2, 0}, {"id_c", 3, 0}}},
+		{" 6", func() { hl.HashAdd("#go", "id_x") },
+			[]TIDCount{{"id_b", 2, 0}, {"id_c", 3, 0}, {"id_x", 1, 0}}},
+		{" 7", func() { hl.TagRename("#go", "#golang") },
+			nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn()
+			if got := hl.TagCounts('#', "go"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagCounts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	want := []TIDCount{{"id_b", 2, 0}, {"id_c", 3, 0}, {"id_x", 1, 0}}
+	if got := hl.TagCounts('#', "golang"); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.TagCounts() = %v, want %v", got, want)
+	}
+	if hl.TagDelete("#golang"); 0 != len(hl.oc) {
+		t.Errorf("THashList.TagDelete() = %v, want %v", hl.oc, nil)
+	}
+} // TestTHashList_occurrenceChanges()
+
+func TestTHashList_occurrenceStorage(t *testing.T) {
+	fn1, fn2, fn3 := delDB("occurrence.db"), delDB("occurrence.txt"), delDB("occurrence.journal.db")
+	defer delDB(fn1)
+	defer delDB(fn2)
+	defer delDB(fn3)
+	tests := []struct {
+		name  string
+		fn    string
+		opts  []TOption
+		store bool
+	}{
+		{" 1", fn1, []TOption{WithFormat(FormatBinary)}, true},
+		{" 2", fn2, []TOption{WithFormat(FormatText)}, true},
+		{" 3", fn3, nil, false},
+	}
+	wantStr := "[@bob]\nid_a\nid_c\n[#go]\nid_a\t3\nid_b\n[#rust]\nid_b\nid_c\t2\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl1, _ := New(tt.fn, append([]TOption{WithOccurrences(true)}, tt.opts...)...)
+			hl1.IDparse("id_a", []byte("#go, #Go and #GO @bob")).
+				IDparse("id_b", []byte("#go #rust")).
+				IDparse("id_c", []byte("#rust #rust @bob"))
+			if got := hl1.String(); got != wantStr {
+				t.Errorf("THashList.String() = %q, want %q", got, wantStr)
+			}
+			if tt.store {
+				if _, err := hl1.Store(); nil != err {
+					t.Errorf("THashList.Store() error = %v", err)
+					return
+				}
+			} else if err := hl1.Flush(); nil != err {
+				t.Errorf("THashList.Flush() error = %v", err)
+				return
+			}
+			hl2, err := New(tt.fn, append(tt.opts, WithOccurrences(true))...)
+			if nil != err {
+				t.Errorf("New() error = %v", err)
+				return
+			}
+			if got := hl2.String(); got != wantStr {
+				t.Errorf("New() = %q, want %q", got, wantStr)
+			}
+			if got, want := hl2.TagCount('#', "go", "id_a"), 3; got != want {
+				t.Errorf("THashList.TagCount() = %v, want %v", got, want)
+			}
+		})
+	}
+} // TestTHashList_occurrenceStorage()
+
+func TestTHashList_occurrenceMerge(t *testing.T) {
+	data := newStorageData()
+	// keys written by earlier versions collapse to `#go`:
+	data.Tags = map[string][]string{
+		"#go": {"id_a", "id_b"},
+		"#Go": {"id_a", "id_b", "id_c"},
+		"#GO": {"id_b"},
+	}
+	data.Counts = map[string]map[string]int{
+		"#go": {"id_a": 3},
+		"#Go": {"id_a": 2, "id_c": 4},
+	}
+	hl, _ := New("", WithStorage(&tMemStorage{data: data}), WithOccurrences(true))
+	want := []TIDCount{{"id_a", 5, 0}, {"id_b", 3, 0}, {"id_c", 4, 0}}
+	if got := hl.TagCounts('#', "go"); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.TagCounts() = %v, want %v", got, want)
+	}
+} // TestTHashList_occurrenceMerge()
+
+func TestWithOccurrences(t *testing.T) {
+	hl, _ := New("", WithOccurrences(false))
+	hl.IDparse("id_a", []byte("#go #go #go"))
+	if got, want := hl.TagCount('#', "go", "id_a"), 1; got != want {
+		t.Errorf("THashList.TagCount() = %v, want %v", got, want)
+	}
+	if got, want := hl.CountedList(), []TCountItem{{1, "#go"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.CountedList() = %v, want %v", got, want)
+	}
+	if got, want := hl.String(), "[#go]\nid_a\n"; got != want {
+		t.Errorf("THashList.String() = %q, want %q", got, want)
+	}
+} // TestWithOccurrences()
+
+/* _EoF_ */
@@ -0,0 +1,228 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"time"
+)
+
+type (
+	// TPersistMode determines when a `THashList` writes its
+	// modifications to its storage.
+	TPersistMode int
+
+	// TPersistPolicy configures when a `THashList` writes its
+	// modifications to its storage.
+	//
+	// @see WithPersistence()
+	TPersistPolicy struct {
+		// Mode selects the policy to use.
+		Mode TPersistMode
+
+		// MaxChanges is the number of modifications after which
+		// the list is written (only used by `PersistDebounced`).
+		MaxChanges int
+
+		// Delay is the time after the first unwritten modification
+		// when the list is written (only used by `PersistDebounced`).
+		Delay time.Duration
+	}
+)
+
+const (
+	// PersistImmediate writes each modification as soon as it
+	// happens (which is the default).
+	PersistImmediate TPersistMode = iota
+
+	// PersistNever doesn't write modifications automatically;
+	// they are only written by `Flush()`, `Close()` or `Store()`.
+	PersistNever
+
+	// PersistDebounced writes modifications after `MaxChanges`
+	// modifications or `Delay` time, whichever comes first.
+	PersistDebounced
+)
+
+// `changed()` persists the recorded modifications according to
+// the list's persistence policy returning a possible error.
+func (hl *THashList) changed() error {
+	// the mutex.Lock is done by the callers
+
+	if 0 == len(hl.µPending) {
+		return nil
+	}
+	switch hl.policy.Mode {
+	case PersistNever:
+		return nil
+
+	case PersistDebounced:
+		if (0 < hl.policy.MaxChanges) && (len(hl.µPending) >= hl.policy.MaxChanges) {
+			return hl.flush()
+		}
+		if (0 < hl.policy.Delay) && (nil == hl.µTimer) {
+			hl.µTimer = time.AfterFunc(hl.policy.Delay, hl.flushTimer)
+		}
+		return nil
+
+	default:
+		return hl.persist()
+	}
+} // changed()
+
+// Close writes all pending modifications to the list's storage
+// and closes that storage returning a possible error.
+//
+// The list shouldn't be modified after calling this method.
+func (hl *THashList) Close() error {
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	err := hl.flush()
+	if nil != hl.st {
+		if cErr := hl.st.Close(); nil == err {
+			err = cErr
+		}
+	}
+
+	return err
+} // Close()
+
+// `flush()` writes all pending modifications to the list's storage
+// returning a possible error.
+func (hl *THashList) flush() error {
+	// the mutex.Lock is done by the callers
+
+	if nil != hl.µTimer {
+		hl.µTimer.Stop()
+		hl.µTimer = nil
+	}
+
+	return hl.persist()
+} // flush()
+
+// Err returns the error of the last failed attempt to write the
+// list's modifications to its storage.
+//
+// The result is `nil` if the last attempt succeeded. Since the
+// mutating methods (like e.g. `IDparse()`) return the list itself
+// this method should be used to check whether their modifications
+// could be written.
+func (hl *THashList) Err() error {
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	return hl.µErr
+} // Err()
+
+// Flush writes all pending modifications to the list's storage
+// returning a possible error.
+//
+// This method is only needed if the list's persistence policy is
+// not `PersistImmediate` (which is the default).
+func (hl *THashList) Flush() error {
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	return hl.flush()
+} // Flush()
+
+// `flushTimer()` is called by the timer started by `changed()`
+// to write the pending modifications.
+func (hl *THashList) flushTimer() {
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	hl.µTimer = nil
+	_ = hl.persist()
+} // flushTimer()
+
+// `persist()` writes all modifications recorded since the last call
+// to the list's storage returning a possible error.
+//
+// The error (if any) is remembered for `Err()` and passed to the
+// list's error handler. The modifications are kept in case of an
+// error, so they are written by the next call.
+func (hl *THashList) persist() error {
+	// the mutex.Lock is done by the callers
+
+	if 0 == len(hl.µPending) {
+		return nil
+	}
+	err := hl.persist0()
+	hl.µErr = err
+	if (nil != err) && (nil != hl.onErr) {
+		hl.onErr(err)
+	}
+
+	return err
+} // persist()
+
+// `persist0()` writes all modifications recorded since the last call
+// to the list's storage returning a possible error.
+//
+// If the storage implements the `TJournal` interface the recorded
+// modifications are appended to its journal; once the journal
+// exceeds its configured size the whole list is written instead.
+func (hl *THashList) persist0() error {
+	// the mutex.Lock is done by the callers
+
+	st := hl.storage()
+	if nil == st {
+		hl.µPending = nil
+		return nil
+	}
+	if journal, ok := st.(TJournal); ok && (0 <= hl.jmax) {
+		size, err := journal.Append(hl.µPending)
+		if nil != err {
+			return err
+		}
+		hl.µPending = nil
+		limit := hl.jmax
+		if 0 == limit {
+			limit = defaultJournalSize
+		}
+		if size <= limit {
+			return nil
+		}
+	}
+	_, err := hl.store()
+
+	return err
+} // persist0()
+
+// WithErrorHandler returns an option to set a function called
+// whenever writing the list's modifications to its storage fails.
+//
+// The function is called while the list is locked, i.e. it must
+// not call any of the list's methods; it may, however, start
+// another goroutine doing so (e.g. to retry by calling `Flush()`).
+//
+// `aHandler` is the function to call with the respective error.
+func WithErrorHandler(aHandler func(aErr error)) TOption {
+	return func(aList *THashList) {
+		aList.onErr = aHandler
+	}
+} // WithErrorHandler()
+
+// WithPersistence returns an option to set the list's policy
+// of writing modifications to its storage.
+//
+// With `PersistDebounced` the modifications are written by a
+// background goroutine; a `MaxChanges` value of zero disables
+// writing after a number of modifications while a `Delay` of
+// zero disables writing after some time.
+//
+// `aPolicy` is the persistence policy to use.
+func WithPersistence(aPolicy TPersistPolicy) TOption {
+	return func(aList *THashList) {
+		aList.policy = aPolicy
+	}
+} // WithPersistence()
+
+/* _EoF_ */
@@ -0,0 +1,167 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// `tFailStorage` is a `TStorage` failing to save while `fail` is set.
+type tFailStorage struct {
+	tMemStorage
+	fail bool
+}
+
+func (fs *tFailStorage) Save(aData *TStorageData) (int, error) {
+	if fs.fail {
+		return 0, errors.New("disk full")
+	}
+
+	return fs.tMemStorage.Save(aData)
+} // Save()
+
+func TestWithPersistence(t *testing.T) {
+	hash1, hash2, hash3 := "#hash1", "#hash2", "@mention3"
+	id1, id2 := "id_c", "id_a"
+	ms1 := &tMemStorage{}
+	hl1, _ := New("", WithStorage(ms1), WithJournal(-1),
+		WithPersistence(TPersistPolicy{Mode: PersistNever}))
+	hl1.HashAdd(hash1, id1).
+		HashAdd(hash2, id2)
+	ms2 := &tMemStorage{}
+	hl2, _ := New("", WithStorage(ms2), WithJournal(-1),
+		WithPersistence(TPersistPolicy{Mode: PersistDebounced, MaxChanges: 3}))
+	hl2.HashAdd(hash1, id1).
+		HashAdd(hash2, id2).
+		MentionAdd(hash3, id1)
+	ms4 := &tMemStorage{}
+	hl4, _ := New("", WithStorage(ms4), WithJournal(-1),
+		WithPersistence(TPersistPolicy{Mode: PersistDebounced, MaxChanges: 3}))
+	hl4.HashAdd(hash1, id1)
+	tests := []struct {
+		name  string
+		ms    *tMemStorage
+		want  int
+		flush *THashList
+		after int
+	}{
+		// TODO: Add test cases.
+		{" 1", ms1, 0, hl1, 2},
+		{" 2", ms2, 3, nil, 3},
+		{" 3", ms4, 0, hl4, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ms.len(); got != tt.want {
+				t.Errorf("THashList.changed() = %v, want %v", got, tt.want)
+			}
+			if nil != tt.flush {
+				if err := tt.flush.Flush(); nil != err {
+					t.Errorf("THashList.Flush() error = %v", err)
+				}
+			}
+			if got := tt.ms.len(); got != tt.after {
+				t.Errorf("THashList.Flush() = %v, want %v", got, tt.after)
+			}
+		})
+	}
+} // TestWithPersistence()
+
+func TestTHashList_flushTimer(t *testing.T) {
+	ms := &tMemStorage{}
+	hl1, _ := New("", WithStorage(ms), WithJournal(-1),
+		WithPersistence(TPersistPolicy{Mode: PersistDebounced, Delay: 50 * time.Millisecond}))
+	hl1.HashAdd("#hash1", "id_a").
+		HashAdd("#hash2", "id_a")
+	if got := ms.len(); 0 != got {
+		t.Errorf("THashList.changed() = %v, want %v", got, 0)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if got := ms.len(); 2 != got {
+		t.Errorf("THashList.flushTimer() = %v, want %v", got, 2)
+	}
+} // TestTHashList_flushTimer()
+
+func TestTHashList_Walk(t *testing.T) {
+	ms := &tMemStorage{}
+	hl1, _ := New("", WithStorage(ms), WithJournal(-1),
+		WithPersistence(TPersistPolicy{Mode: PersistDebounced, Delay: time.Millisecond}))
+	defer hl1.Close()
+	for i := 0; 100 > i; i++ {
+		hl1.HashAdd("#hash"+strconv.Itoa(i), "id_a").
+			HashAdd("#hash"+strconv.Itoa(i), "id_b")
+	}
+	// the flush timer runs concurrently to the traversal:
+	hl1.Walk(func(aHash, aID string) bool {
+		time.Sleep(10 * time.Microsecond)
+		return "id_a" != aID
+	})
+	if got, want := hl1.IDlist("id_a"), []string(nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Walk() = %v, want %v", got, want)
+	}
+	if got, want := hl1.HashLen("#hash7"), 1; got != want {
+		t.Errorf("THashList.HashLen() = %v, want %v", got, want)
+	}
+	// calling the list's methods doesn't deadlock:
+	hl1.Walk(func(aHash, aID string) bool {
+		return 0 < hl1.HashLen(aHash)
+	})
+	if err := hl1.Flush(); nil != err {
+		t.Errorf("THashList.Flush() error = %v", err)
+	}
+	if got, want := ms.len(), 100; got != want {
+		t.Errorf("THashList.Walk() = %v, want %v", got, want)
+	}
+} // TestTHashList_Walk()
+
+func TestTHashList_Err(t *testing.T) {
+	var handled []error
+	fs := &tFailStorage{fail: true}
+	hl1, _ := New("", WithStorage(fs), WithErrorHandler(func(aErr error) {
+		handled = append(handled, aErr)
+	}))
+	hl1.IDparse("id_a", []byte("blabla #hash1 blabla"))
+	if nil == hl1.Err() {
+		t.Errorf("THashList.Err() = %v, want an error", hl1.Err())
+	}
+	hl1.HashAdd("#hash2", "id_b")
+	if 2 != len(handled) {
+		t.Errorf("WithErrorHandler() calls = %v, want %v", len(handled), 2)
+	}
+	fs.fail = false
+	if err := hl1.Flush(); nil != err {
+		t.Errorf("THashList.Flush() error = %v", err)
+	}
+	if nil != hl1.Err() {
+		t.Errorf("THashList.Err() = %v, want %v", hl1.Err(), nil)
+	}
+	if got := fs.len(); 2 != got {
+		t.Errorf("THashList.Flush() = %v, want %v", got, 2)
+	}
+} // TestTHashList_Err()
+
+func TestTHashList_Close(t *testing.T) {
+	ms := &tMemStorage{}
+	hl1, _ := New("", WithStorage(ms),
+		WithPersistence(TPersistPolicy{Mode: PersistNever}))
+	hl1.HashAdd("#hash1", "id_a")
+	if err := hl1.Close(); nil != err {
+		t.Errorf("THashList.Close() error = %v", err)
+	}
+	if !ms.closed {
+		t.Errorf("THashList.Close() closed = %v, want %v", ms.closed, true)
+	}
+	if got := ms.len(); 1 != got {
+		t.Errorf("THashList.Close() = %v, want %v", got, 1)
+	}
+} // TestTHashList_Close()
+
+/* _EoF_ */
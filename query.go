@@ -0,0 +1,446 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// `tQueryToken` is a single token of a query expression.
+	tQueryToken struct {
+		kind tQueryKind // type of token
+		text string     // the token's text
+		pos  int        // the token's byte offset in the query
+	}
+
+	// `tQueryKind` identifies the type of a `tQueryToken`.
+	tQueryKind int
+
+	// `tQueryNode` is a node of a parsed query expression.
+	tQueryNode struct {
+		kind  tQueryKind  // qkTerm, qkAnd, qkOr or qkNot
+		term  string      // list index of a qkTerm node
+		tree  bool        // include the descendants of a qkTerm node
+		left  *tQueryNode // (first) operand of operators
+		right *tQueryNode // second operand of qkAnd and qkOr
+	}
+
+	// `tQueryParser` is a recursive descent parser of queries.
+	tQueryParser struct {
+		tokens []tQueryToken
+		pos    int      // index of the next token
+		sigils []TSigil // kinds of tags accepted as terms
+		sep    byte     // separator of hierarchical tags
+	}
+
+	// `tQueryEnv` holds the state of evaluating a query.
+	tQueryEnv struct {
+		all  []string  // all IDs (computed on demand for NOT)
+		from time.Time // start of the time range (zero: unbounded)
+		to   time.Time // end of the time range (zero: unbounded)
+	}
+)
+
+const (
+	qkEnd tQueryKind = iota
+	qkTerm
+	qkAnd
+	qkOr
+	qkNot
+	qkOpen
+	qkClose
+)
+
+// `queryError()` returns an error describing a syntax error
+// in a query.
+//
+// `aToken` is the token causing the error.
+func queryError(aToken tQueryToken) error {
+	if qkEnd == aToken.kind {
+		return fmt.Errorf("hashtags: query: unexpected end of query")
+	}
+
+	return fmt.Errorf("hashtags: query: unexpected %q at position %d",
+		aToken.text, aToken.pos)
+} // queryError()
+
+// `scanQuery()` splits `aQuery` into tokens.
+//
+// `aQuery` is the query expression to split.
+func scanQuery(aQuery string) (rTokens []tQueryToken) {
+	for pos := 0; pos < len(aQuery); {
+		r, size := utf8.DecodeRuneInString(aQuery[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+		case '(' == r:
+			rTokens = append(rTokens, tQueryToken{qkOpen, "(", pos})
+			pos++
+			continue
+		case ')' == r:
+			rTokens = append(rTokens, tQueryToken{qkClose, ")", pos})
+			pos++
+			continue
+		case '!' == r, '-' == r:
+			rTokens = append(rTokens, tQueryToken{qkNot, string(r), pos})
+			pos++
+			continue
+		case '&' == r, '|' == r:
+			end := pos + 1
+			if (end < len(aQuery)) && (aQuery[end] == aQuery[pos]) {
+				end++ // accept `&&` and `||` as well
+			}
+			kind := qkAnd
+			if '|' == r {
+				kind = qkOr
+			}
+			rTokens = append(rTokens, tQueryToken{kind, aQuery[pos:end], pos})
+			pos = end
+			continue
+		}
+
+		end := pos
+		for end < len(aQuery) {
+			r, size = utf8.DecodeRuneInString(aQuery[end:])
+			if unicode.IsSpace(r) || strings.ContainsRune("()&|", r) {
+				break
+			}
+			end += size
+		}
+		word := aQuery[pos:end]
+		token := tQueryToken{qkTerm, word, pos}
+		switch strings.ToUpper(word) {
+		case "AND":
+			token.kind = qkAnd
+		case "OR":
+			token.kind = qkOr
+		case "NOT":
+			token.kind = qkNot
+		}
+		rTokens = append(rTokens, token)
+		pos = end
+	}
+
+	return
+} // scanQuery()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `next()` returns the next token without consuming it.
+func (qp *tQueryParser) next() tQueryToken {
+	if qp.pos < len(qp.tokens) {
+		return qp.tokens[qp.pos]
+	}
+
+	return tQueryToken{kind: qkEnd}
+} // next()
+
+// `parse()` returns the syntax tree of the parser's tokens.
+func (qp *tQueryParser) parse() (*tQueryNode, error) {
+	node, err := qp.parseOr()
+	if nil != err {
+		return nil, err
+	}
+	if token := qp.next(); qkEnd != token.kind {
+		return nil, queryError(token)
+	}
+
+	return node, nil
+} // parse()
+
+// `parseOr()` parses a list of AND-expressions joined by OR.
+func (qp *tQueryParser) parseOr() (*tQueryNode, error) {
+	left, err := qp.parseAnd()
+	if nil != err {
+		return nil, err
+	}
+	for qkOr == qp.next().kind {
+		qp.pos++
+		right, err := qp.parseAnd()
+		if nil != err {
+			return nil, err
+		}
+		left = &tQueryNode{kind: qkOr, left: left, right: right}
+	}
+
+	return left, nil
+} // parseOr()
+
+// `parseAnd()` parses a list of NOT-expressions joined by AND.
+//
+// Two expressions without an operator between them are
+// joined by an implicit AND.
+func (qp *tQueryParser) parseAnd() (*tQueryNode, error) {
+	left, err := qp.parseNot()
+	if nil != err {
+		return nil, err
+	}
+	for {
+		switch qp.next().kind {
+		case qkAnd:
+			qp.pos++
+		case qkTerm, qkNot, qkOpen:
+			// implicit AND
+		default:
+			return left, nil
+		}
+		right, err := qp.parseNot()
+		if nil != err {
+			return nil, err
+		}
+		left = &tQueryNode{kind: qkAnd, left: left, right: right}
+	}
+} // parseAnd()
+
+// `parseNot()` parses an optionally negated primary expression.
+func (qp *tQueryParser) parseNot() (*tQueryNode, error) {
+	if qkNot == qp.next().kind {
+		qp.pos++
+		operand, err := qp.parseNot()
+		if nil != err {
+			return nil, err
+		}
+
+		return &tQueryNode{kind: qkNot, left: operand}, nil
+	}
+
+	return qp.parsePrimary()
+} // parseNot()
+
+// `parsePrimary()` parses a term or a parenthesised expression.
+func (qp *tQueryParser) parsePrimary() (*tQueryNode, error) {
+	token := qp.next()
+	switch token.kind {
+	case qkOpen:
+		qp.pos++
+		node, err := qp.parseOr()
+		if nil != err {
+			return nil, err
+		}
+		if token = qp.next(); qkClose != token.kind {
+			return nil, queryError(token)
+		}
+		qp.pos++
+
+		return node, nil
+
+	case qkTerm:
+		if (1 >= len(token.text)) || (nil == findSigil(sigilsOf(qp.sigils), token.text[0])) {
+			return nil, queryError(token)
+		}
+		qp.pos++
+		text, tree := token.text, false
+		if (0 != qp.sep) && strings.HasSuffix(text, string(qp.sep)+"*") {
+			// `#tag/*` includes the tag's descendants
+			text, tree = strings.TrimRight(text[:len(text)-1], string(qp.sep)), true
+			if 1 >= len(text) {
+				return nil, queryError(token)
+			}
+		}
+
+		return &tQueryNode{
+			kind: qkTerm,
+			term: mapIndex(text[0], text),
+			tree: tree,
+		}, nil
+	}
+
+	return nil, queryError(token)
+} // parsePrimary()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `idDifference()` returns the IDs of `aList` not in `aOther`.
+//
+// Both lists must be sorted.
+func idDifference(aList, aOther []string) []string {
+	result := make([]string, 0, len(aList))
+	j := 0
+	for _, id := range aList {
+		for (j < len(aOther)) && (aOther[j] < id) {
+			j++
+		}
+		if (j < len(aOther)) && (aOther[j] == id) {
+			continue
+		}
+		result = append(result, id)
+	}
+
+	return result
+} // idDifference()
+
+// `idIntersection()` returns the IDs in both `aList` and `aOther`.
+//
+// Both lists must be sorted.
+func idIntersection(aList, aOther []string) []string {
+	result := make([]string, 0, len(aList))
+	for i, j := 0, 0; (i < len(aList)) && (j < len(aOther)); {
+		switch {
+		case aList[i] < aOther[j]:
+			i++
+		case aList[i] > aOther[j]:
+			j++
+		default:
+			result = append(result, aList[i])
+			i++
+			j++
+		}
+	}
+
+	return result
+} // idIntersection()
+
+// `idUnion()` returns the IDs in either `aList` or `aOther`.
+//
+// Both lists must be sorted.
+func idUnion(aList, aOther []string) []string {
+	result := make([]string, 0, len(aList)+len(aOther))
+	i, j := 0, 0
+	for (i < len(aList)) && (j < len(aOther)) {
+		switch {
+		case aList[i] < aOther[j]:
+			result = append(result, aList[i])
+			i++
+		case aList[i] > aOther[j]:
+			result = append(result, aOther[j])
+			j++
+		default:
+			result = append(result, aList[i])
+			i++
+			j++
+		}
+	}
+	result = append(result, aList[i:]...)
+
+	return append(result, aOther[j:]...)
+} // idUnion()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `allIDs()` returns the sorted list of all IDs in the list.
+//
+// `aEnv` provides the time range of the associations to consider.
+func (hl *THashList) allIDs(aEnv *tQueryEnv) []string {
+	// the mutex.Lock is done by the callers
+
+	if !aEnv.from.IsZero() || !aEnv.to.IsZero() {
+		return hl.seenIDs(func(string) bool {
+			return true
+		}, aEnv.from, aEnv.to)
+	}
+	ix := hl.index()
+	result := make([]string, 0, len(ix))
+	for id := range ix {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+
+	return result
+} // allIDs()
+
+// `evalQuery()` returns the sorted IDs matching `aNode`.
+//
+// `aNode` is the syntax tree to evaluate.
+//
+// `aEnv` is the state of the query's evaluation.
+func (hl *THashList) evalQuery(aNode *tQueryNode, aEnv *tQueryEnv) []string {
+	// the mutex.Lock is done by the callers
+
+	switch aNode.kind {
+	case qkTerm:
+		if !aEnv.from.IsZero() || !aEnv.to.IsZero() {
+			index := hl.alias(aNode.term)
+			return hl.seenIDs(func(aMapIdx string) bool {
+				return (aMapIdx == index) || (aNode.tree &&
+					(0 != hl.sep) && isDescendant(aMapIdx, index, hl.sep))
+			}, aEnv.from, aEnv.to)
+		}
+		if aNode.tree {
+			return hl.treeIDs(hl.alias(aNode.term))
+		}
+		if sl, ok := hl.hl[hl.alias(aNode.term)]; ok {
+			return append([]string(nil), (*sl)...)
+		}
+		return []string{}
+
+	case qkAnd:
+		if qkNot == aNode.right.kind {
+			// avoid computing the complement
+			return idDifference(hl.evalQuery(aNode.left, aEnv),
+				hl.evalQuery(aNode.right.left, aEnv))
+		}
+		return idIntersection(hl.evalQuery(aNode.left, aEnv),
+			hl.evalQuery(aNode.right, aEnv))
+
+	case qkOr:
+		return idUnion(hl.evalQuery(aNode.left, aEnv),
+			hl.evalQuery(aNode.right, aEnv))
+
+	case qkNot:
+		if nil == aEnv.all {
+			aEnv.all = hl.allIDs(aEnv)
+		}
+		return idDifference(aEnv.all, hl.evalQuery(aNode.left, aEnv))
+	}
+
+	return []string{}
+} // evalQuery()
+
+// Query returns the sorted list of IDs matching `aQuery`
+// and a possible syntax error.
+//
+// The query consists of #hashtags and @mentions (or other tags,
+// see `WithSigil()`) combined by the (case-insensitive) operators
+// `AND` (or `&`), `OR` (or `|`) and `NOT` (or `!`, `-`) which can
+// be grouped by parentheses.
+// Two terms without an operator between them are combined by
+// `AND`; `NOT` binds stronger than `AND` which in turn binds
+// stronger than `OR`. With hierarchical tags (see `WithHierarchy()`)
+// a term like `#lang/*` matches `#lang` and all its descendants.
+// For example:
+//
+//	#golang #performance NOT @bob
+//	(#golang OR #rust) AND NOT (#beginner | @bob)
+//
+// `aQuery` is the query expression to evaluate.
+func (hl *THashList) Query(aQuery string) ([]string, error) {
+	return hl.query(aQuery, &tQueryEnv{})
+} // Query()
+
+// `query()` returns the sorted list of IDs matching `aQuery`
+// and a possible syntax error.
+//
+// `aQuery` is the query expression to evaluate.
+//
+// `aEnv` provides the time range of the associations to consider.
+func (hl *THashList) query(aQuery string, aEnv *tQueryEnv) ([]string, error) {
+	parser := &tQueryParser{
+		tokens: scanQuery(aQuery),
+		sigils: hl.sigilList(),
+		sep:    hl.sep,
+	}
+	node, err := parser.parse()
+	if nil != err {
+		return nil, err
+	}
+
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	return hl.evalQuery(node, aEnv), nil
+} // query()
+
+/* _EoF_ */
@@ -0,0 +1,58 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTHashList_Query(t *testing.T) {
+	hl1, _ := New("")
+	hl1.IDparse("id_1", []byte("#golang #performance")).
+		IDparse("id_2", []byte("#golang #performance @bob")).
+		IDparse("id_3", []byte("#golang #beginner")).
+		IDparse("id_4", []byte("#rust #performance")).
+		IDparse("id_5", []byte("@bob says #go-lang"))
+	tests := []struct {
+		name    string
+		query   string
+		want    []string
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{" 1", "#golang", []string{"id_1", "id_2", "id_3"}, false},
+		{" 2", "#GoLang AND #performance", []string{"id_1", "id_2"}, false},
+		{" 3", "#golang #performance NOT @bob", []string{"id_1"}, false},
+		{" 4", "#golang && #performance && !@bob", []string{"id_1"}, false},
+		{" 5", "#rust OR #beginner", []string{"id_3", "id_4"}, false},
+		{" 6", "(#golang | #rust) and not (#beginner or @bob)", []string{"id_1", "id_4"}, false},
+		{" 7", "NOT #performance", []string{"id_3", "id_5"}, false},
+		{" 8", "-#golang -#rust", []string{"id_5"}, false},
+		{" 9", "#go-lang OR #does.not.exist", []string{"id_5"}, false},
+		{"10", "#unknown", []string{}, false},
+		{"11", "#golang AND", nil, true},
+		{"12", "(#golang", nil, true},
+		{"13", "golang", nil, true},
+		{"14", "#golang)", nil, true},
+		{"15", "", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hl1.Query(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("THashList.Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_Query()
+
+/* _EoF_ */
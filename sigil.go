@@ -0,0 +1,224 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"strings"
+	"unicode"
+)
+
+type (
+	// TSigil describes a kind of tag (like #hashtags or @mentions)
+	// identified by the character it starts with.
+	//
+	// @see WithSigil()
+	TSigil struct {
+		// Char is the character starting a tag (e.g. '$' for
+		// cashtags or '+' for group references).
+		Char byte
+
+		// First reports whether `aRune` may directly follow `Char`.
+		//
+		// If `nil` the rules of #hashtags are used: letters,
+		// numbers and connector punctuation (like '_').
+		First func(aRune rune) bool
+
+		// Next reports whether `aRune` may be part of the tag after
+		// its first character.
+		//
+		// If `nil` the rules of #hashtags are used: letters, numbers,
+		// combining marks, connector punctuation and the zero width
+		// (non-)joiners.
+		Next func(aRune rune) bool
+
+		// separator of hierarchical tags (set by the list)
+		sep byte
+	}
+
+	// `tSigilTokenizer` is implemented by the tokenizers of this
+	// package which can handle sigils other than '#' and '@'.
+	tSigilTokenizer interface {
+		// `withSigils()` returns a copy of the tokenizer using
+		// `aSigils` to find tags.
+		withSigils(aSigils []TSigil) TTokenizer
+	}
+)
+
+// `defaultSigils` are the sigils every list knows.
+var defaultSigils = []TSigil{
+	{Char: '#'},
+	{Char: '@'},
+}
+
+// Characters which can't be used as sigils since they're part of
+// the query language, the text file format or common punctuation.
+const sigilReserved = "!\"&'()*,-./:;<=>?[\\]_`{|}"
+
+// `first()` reports whether `aRune` may directly follow the sigil.
+func (s *TSigil) first(aRune rune) bool {
+	if nil != s.First {
+		return s.First(aRune)
+	}
+
+	// Following the identifier rules of Unicode UAX #31 a tag
+	// starts with a letter, number or connector punctuation;
+	// additionally '§' and '-' are accepted for historical reasons.
+	return unicode.IsLetter(aRune) || unicode.IsNumber(aRune) ||
+		unicode.Is(unicode.Pc, aRune) || ('§' == aRune) || ('-' == aRune)
+} // first()
+
+// `next()` reports whether `aRune` may be part of the tag after
+// its first character.
+func (s *TSigil) next(aRune rune) bool {
+	if nil != s.Next {
+		return s.Next(aRune)
+	}
+
+	// The zero width (non-)joiners are needed by some scripts
+	// (e.g. Persian or Devanagari).
+	return unicode.IsLetter(aRune) || unicode.IsNumber(aRune) ||
+		unicode.IsMark(aRune) || unicode.Is(unicode.Pc, aRune) ||
+		('§' == aRune) || ('-' == aRune) ||
+		('\u200C' == aRune) || ('\u200D' == aRune)
+} // next()
+
+// `findSigil()` returns the entry of `aSigils` for `aChar` or `nil`
+// if there's none.
+func findSigil(aSigils []TSigil, aChar byte) *TSigil {
+	for idx := range aSigils {
+		if aChar == aSigils[idx].Char {
+			return &aSigils[idx]
+		}
+	}
+
+	return nil
+} // findSigil()
+
+// `sigilsOf()` returns `aSigils` or the default sigils if `aSigils`
+// is empty.
+func sigilsOf(aSigils []TSigil) []TSigil {
+	if 0 == len(aSigils) {
+		return defaultSigils
+	}
+
+	return aSigils
+} // sigilsOf()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `isSigil()` returns whether `aChar` is one of the list's sigils.
+func (hl *THashList) isSigil(aChar byte) bool {
+	return nil != findSigil(hl.sigilList(), aChar)
+} // isSigil()
+
+// `sigilList()` returns all sigils known by the list.
+func (hl *THashList) sigilList() []TSigil {
+	return sigilsOf(hl.sigils)
+} // sigilList()
+
+// TagAdd appends `aID` to the list of `aTag` starting with `aSigil`.
+//
+// If either `aTag` or `aID` are empty strings or `aSigil` is not
+// registered with the list (see `WithSigil()`) they are silently
+// ignored (i.e. this method does nothing).
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` is the list index to lookup.
+//
+// `aID` is to be added to the tag's list.
+func (hl *THashList) TagAdd(aSigil byte, aTag, aID string) *THashList {
+	if !hl.isSigil(aSigil) {
+		return hl
+	}
+	hl.mtx.Lock()
+	defer hl.mtx.Unlock()
+
+	_ = hl.add(aSigil, aTag, aID).changed()
+
+	return hl
+} // TagAdd()
+
+// TagLen returns the number of IDs stored for `aTag` starting
+// with `aSigil` (or `-1` if there's no such tag).
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+func (hl *THashList) TagLen(aSigil byte, aTag string) int {
+	if !hl.isSigil(aSigil) {
+		return -1
+	}
+
+	return hl.idxLen(aSigil, aTag)
+} // TagLen()
+
+// TagList returns a list of IDs associated with `aTag` starting
+// with `aSigil`.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+func (hl *THashList) TagList(aSigil byte, aTag string) []string {
+	if !hl.isSigil(aSigil) {
+		return nil
+	}
+
+	return hl.list(aSigil, aTag)
+} // TagList()
+
+// TagRemove deletes `aID` from the list of `aTag` starting
+// with `aSigil`.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+//
+// `aID` is the source to remove from the list.
+func (hl *THashList) TagRemove(aSigil byte, aTag, aID string) *THashList {
+	if !hl.isSigil(aSigil) {
+		return hl
+	}
+
+	return hl.remove(aSigil, aTag, aID)
+} // TagRemove()
+
+// WithSigil returns an option to register an additional kind of
+// tags (besides #hashtags and @mentions) with the list.
+//
+// The list's tokenizer finds those tags in the texts given to e.g.
+// `IDparse()` (custom tokenizers have to return them themselves)
+// and they can be used with `TagAdd()`, `TagLen()`, `TagList()`,
+// `TagRemove()` and `Query()` just like #hashtags and @mentions.
+//
+// `aSigil.Char` must be one of the ASCII characters `#$%+@^~`;
+// otherwise the option is ignored. Registering '#' or '@' replaces
+// their default rules.
+//
+// `aSigil` describes the additional tags.
+func WithSigil(aSigil TSigil) TOption {
+	return func(aList *THashList) {
+		if !unicode.IsPunct(rune(aSigil.Char)) && !unicode.IsSymbol(rune(aSigil.Char)) {
+			return
+		}
+		if (0x80 <= aSigil.Char) || strings.ContainsRune(sigilReserved, rune(aSigil.Char)) {
+			return
+		}
+		if 0 == len(aList.sigils) {
+			aList.sigils = append([]TSigil(nil), defaultSigils...)
+		}
+		if sigil := findSigil(aList.sigils, aSigil.Char); nil != sigil {
+			*sigil = aSigil
+			return
+		}
+		aList.sigils = append(aList.sigils, aSigil)
+	}
+} // WithSigil()
+
+/* _EoF_ */
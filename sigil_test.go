@@ -0,0 +1,140 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+	"unicode"
+)
+
+// `cashtag` are the rules of $TICKER symbols (letters only).
+var cashtag = TSigil{
+	Char:  '$',
+	First: unicode.IsLetter,
+	Next:  unicode.IsLetter,
+}
+
+func TestWithSigil(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []TOption
+		aText string
+		want  []string
+	}{
+		// TODO: Add test cases.
+		{" 0", nil, "#one $AAPL +project", []string{"#one"}},
+		{" 1", []TOption{WithSigil(cashtag)}, "#one $AAPL costs $100", []string{"#one", "$AAPL"}},
+		{" 2", []TOption{WithSigil(TSigil{Char: '+'})}, "@bob in +project_x, 1+2", []string{"@bob", "+project_x"}},
+		{" 3", []TOption{WithSigil(cashtag), WithTokenizer(NewMarkdownTokenizer())}, "`$GOOG` and $MSFT", []string{"$MSFT"}},
+		{" 4", []TOption{WithSigil(cashtag), WithTokenizer(NewHTMLTokenizer())}, "<a href='x$GOOG'>$MSFT</a>", []string{"$MSFT"}},
+		{" 5", []TOption{WithSigil(TSigil{Char: '&'})}, "&amp &reserved", nil},
+		{" 6", []TOption{WithSigil(TSigil{Char: 'x'})}, "xletter", nil},
+		{" 7", []TOption{WithSigil(TSigil{Char: '#', First: unicode.IsLetter})}, "#1st #first", []string{"#first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hl, _ := New("", tt.opts...)
+			if got := matchTags(hl.Matches([]byte(tt.aText))); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WithSigil() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestWithSigil()
+
+func TestTHashList_TagAdd(t *testing.T) {
+	hl, _ := New("", WithSigil(cashtag), WithSigil(TSigil{Char: '+'}))
+	hl.IDparse("id_a", []byte("Buy $AAPL with +Team"))
+	hl.TagAdd('$', "aapl", "id_b").
+		TagAdd('+', "+team", "id_c").
+		TagAdd('%', "unknown", "id_d")
+	type tArgs struct {
+		aSigil byte
+		aTag   string
+	}
+	tests := []struct {
+		name    string
+		args    tArgs
+		wantLen int
+		want    []string
+	}{
+		// TODO: Add test cases.
+		{" 1", tArgs{'$', "$AAPL"}, 2, []string{"id_a", "id_b"}},
+		{" 2", tArgs{'+', "team"}, 2, []string{"id_a", "id_c"}},
+		{" 3", tArgs{'%', "unknown"}, -1, nil},
+		{" 4", tArgs{'#', "aapl"}, -1, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagLen(tt.args.aSigil, tt.args.aTag); got != tt.wantLen {
+				t.Errorf("THashList.TagLen() = %v, want %v", got, tt.wantLen)
+			}
+			if got := hl.TagList(tt.args.aSigil, tt.args.aTag); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	hl.TagRemove('$', "$aapl", "id_a")
+	if got := hl.TagList('$', "$aapl"); !reflect.DeepEqual(got, []string{"id_b"}) {
+		t.Errorf("THashList.TagRemove() = %v, want %v", got, []string{"id_b"})
+	}
+} // TestTHashList_TagAdd()
+
+func TestTHashList_sigilQuery(t *testing.T) {
+	hl, _ := New("", WithSigil(cashtag))
+	hl.IDparse("id_a", []byte("#stocks $AAPL")).
+		IDparse("id_b", []byte("#stocks $MSFT")).
+		IDparse("id_c", []byte("$AAPL $MSFT"))
+	tests := []struct {
+		name    string
+		aQuery  string
+		want    []string
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{" 1", "$aapl", []string{"id_a", "id_c"}, false},
+		{" 2", "#stocks AND NOT $AAPL", []string{"id_b"}, false},
+		{" 3", "$msft | $aapl", []string{"id_a", "id_b", "id_c"}, false},
+		{" 4", "+team", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hl.Query(tt.aQuery)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("THashList.Query() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.Query() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_sigilQuery()
+
+func TestTHashList_sigilStorage(t *testing.T) {
+	fn := delDB("sigil.txt")
+	defer delDB(fn)
+	hl1, _ := New(fn, WithFormat(FormatText), WithSigil(cashtag))
+	hl1.IDparse("id_a", []byte("#stocks $AAPL"))
+	if _, err := hl1.Store(); nil != err {
+		t.Errorf("THashList.Store() error = %v", err)
+		return
+	}
+	hl2, err := New(fn, WithFormat(FormatText), WithSigil(cashtag))
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got, want := hl2.String(), hl1.String(); got != want {
+		t.Errorf("New() = %v, want %v", got, want)
+	}
+	if got := hl2.TagList('$', "$aapl"); !reflect.DeepEqual(got, []string{"id_a"}) {
+		t.Errorf("THashList.TagList() = %v, want %v", got, []string{"id_a"})
+	}
+} // TestTHashList_sigilStorage()
+
+/* _EoF_ */
@@ -0,0 +1,647 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type (
+	// TStorageData is the data exchanged between a `THashList`
+	// and its `TStorage` backend.
+	TStorageData struct {
+		// Tags maps each #hashtag/@mention to the IDs referring to it.
+		Tags map[string][]string
+
+		// Aliases maps each alias to the #hashtag/@mention used
+		// instead (see `AliasAdd()`).
+		Aliases map[string]string
+
+		// Counts maps each #hashtag/@mention to the IDs in whose
+		// text it occurs more than once along with the number of
+		// occurrences (see `WithOccurrences()`).
+		Counts map[string]map[string]int
+
+		// Seen maps each #hashtag/@mention to its IDs along with
+		// the times the association was first and last seen (see
+		// `WithTimestamps()`).
+		Seen map[string]map[string]TSeen
+
+		// Journal lists the modifications to apply to `Tags`;
+		// it's only used by storages implementing `TJournal`.
+		Journal []TJournalEntry
+	}
+
+	// TStorage is the interface of a persistence backend used by
+	// `THashList` to load and save its data.
+	//
+	// Implementations don't need to be safe for concurrent use:
+	// `THashList` serialises all calls to its storage.
+	TStorage interface {
+		// Load returns the data last saved.
+		//
+		// A storage not holding any data yet should return an
+		// empty `TStorageData` and no error.
+		Load() (*TStorageData, error)
+
+		// Save replaces the stored data by `aData` returning
+		// the number of bytes written and a possible error.
+		Save(aData *TStorageData) (int, error)
+
+		// Close releases all resources held by the storage.
+		Close() error
+	}
+
+	// `tBinaryData` is the layout of the data in binary files.
+	//
+	// The aliases, occurrence counts and timestamps (if any) follow
+	// as separate `gob` values so that lists without them are written
+	// as before.
+	tBinaryData struct {
+		Tags map[string][]string
+	}
+
+	// `tFileStorage` provides the file handling shared by
+	// `tBinaryStorage` and `tTextStorage`.
+	tFileStorage struct {
+		fn  string // the filename to use
+		bak bool   // keep the previous generation as backup
+	}
+
+	// `tBinaryStorage` stores the data as a `gob` encoded file.
+	tBinaryStorage struct {
+		tFileStorage
+	}
+
+	// `tTextStorage` stores the data as a plain text file.
+	tTextStorage struct {
+		tFileStorage
+	}
+
+	// `tCountWriter` counts the bytes written to a writer.
+	tCountWriter struct {
+		w io.Writer
+		n int
+	}
+
+	// TFormat identifies the file format used by a `THashList`
+	// which doesn't use a custom storage.
+	//
+	// @see WithFormat()
+	TFormat int
+)
+
+const (
+	// FormatBinary stores the list as `gob` encoded data.
+	FormatBinary TFormat = iota
+
+	// FormatText stores the list as plain text.
+	FormatText
+)
+
+var (
+	// Header written at the start of binary files to tell them
+	// apart from text files when loading.
+	binaryMagic = []byte("#HashTags/gob\n")
+)
+
+const (
+	// Suffix appended to a file's name to get its backup's name.
+	backupSuffix = ".bak"
+)
+
+const (
+	// Number of bytes to inspect when looking for a text file's
+	// first `[#hashtag]` line.
+	textPeekSize = 512
+)
+
+// `newStorageData()` returns a new, empty data container.
+func newStorageData() *TStorageData {
+	return &TStorageData{
+		Tags: make(map[string][]string),
+	}
+} // newStorageData()
+
+// `openRead()` opens `aFilename` for reading.
+//
+// If the file doesn't exist the returned file is `nil`
+// but no error is returned.
+func openRead(aFilename string) (*os.File, error) {
+	file, err := os.OpenFile(aFilename, os.O_RDONLY, 0)
+	if nil != err {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return file, nil
+} // openRead()
+
+// `loadFile()` reads `aFilename` returning the data read and
+// a possible error condition.
+//
+// Binary files are recognised by their `binaryMagic` header while
+// text files start with a `[#hashtag]` line; files having neither
+// are assumed to be binary files written by older versions of
+// this package.
+//
+// `aFilename` is the name of the file to read.
+func loadFile(aFilename string) (*TStorageData, error) {
+	file, err := openRead(aFilename)
+	if (nil != err) || (nil == file) {
+		return newStorageData(), err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	if head, _ := reader.Peek(len(binaryMagic)); bytes.Equal(head, binaryMagic) {
+		_, _ = reader.Discard(len(binaryMagic))
+		return loadBinary(reader, false)
+	}
+	head, err := reader.Peek(textPeekSize)
+	if (nil != err) && (io.EOF != err) && (bufio.ErrBufferFull != err) {
+		return newStorageData(), err
+	}
+	head = bytes.TrimLeft(head, " \t\r\n")
+	if (0 == len(head)) || ('[' == head[0]) {
+		// an empty file is a valid text file
+		return loadText(reader)
+	}
+
+	return loadBinary(reader, true)
+} // loadFile()
+
+// `loadBinary()` decodes `gob` data from `aReader` returning the
+// data read and a possible error condition.
+//
+// `aReader` provides the data to decode.
+//
+// `aLegacy` tells whether the data is a plain map written by
+// older versions of this package.
+func loadBinary(aReader io.Reader, aLegacy bool) (*TStorageData, error) {
+	var (
+		data tBinaryData
+		err  error
+	)
+	decoder := gob.NewDecoder(aReader)
+	if aLegacy {
+		err = decoder.Decode(&data.Tags)
+	} else {
+		err = decoder.Decode(&data)
+	}
+	if nil != err {
+		return newStorageData(), err
+	}
+	result := newStorageData()
+	if nil != data.Tags {
+		// `gob` doesn't transmit empty maps
+		result.Tags = data.Tags
+	}
+	if !aLegacy {
+		// a missing value (`io.EOF`) means no aliases, counts
+		// or timestamps
+		if err = decoder.Decode(&result.Aliases); nil == err {
+			if err = decoder.Decode(&result.Counts); nil == err {
+				err = decoder.Decode(&result.Seen)
+			}
+		}
+		if (nil != err) && (io.EOF != err) {
+			return newStorageData(), err
+		}
+	}
+
+	return result, nil
+} // loadBinary()
+
+// `loadText()` parses text data from `aReader` returning the
+// data read and a possible error condition.
+//
+// This function reads one line at a time.
+//
+// `aReader` provides the text to parse.
+func loadText(aReader io.Reader) (*TStorageData, error) {
+	var mapIdx string
+	result := newStorageData()
+	scanner := bufio.NewScanner(aReader)
+	for lineRead := scanner.Scan(); lineRead; lineRead = scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if 0 == len(line) {
+			continue
+		}
+
+		if matches := aliasHeadRE.FindStringSubmatch(line); nil != matches {
+			if nil == result.Aliases {
+				result.Aliases = make(map[string]string)
+			}
+			result.Aliases[strings.ToLower(matches[1])] = strings.ToLower(matches[2])
+			mapIdx = ""
+		} else if matches := hashHeadRE.FindStringSubmatch(line); nil != matches {
+			mapIdx = strings.ToLower(strings.TrimSpace(matches[1]))
+		} else if 0 < len(mapIdx) {
+			// IDs without a preceding `[#hashtag]` line are ignored
+			id, n, seen := parseIDLine(line)
+			result.Tags[mapIdx] = append(result.Tags[mapIdx], id)
+			if 1 < n {
+				if nil == result.Counts {
+					result.Counts = make(map[string]map[string]int)
+				}
+				if nil == result.Counts[mapIdx] {
+					result.Counts[mapIdx] = make(map[string]int)
+				}
+				result.Counts[mapIdx][id] = n
+			}
+			if !seen.First.IsZero() {
+				if nil == result.Seen {
+					result.Seen = make(map[string]map[string]TSeen)
+				}
+				if nil == result.Seen[mapIdx] {
+					result.Seen[mapIdx] = make(map[string]TSeen)
+				}
+				result.Seen[mapIdx][id] = seen
+			}
+		}
+	}
+
+	return result, scanner.Err()
+} // loadText()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// NewBinaryStorage returns a `TStorage` writing `gob` encoded
+// data to `aFilename`.
+//
+// Loading/storing binary data is about three times as fast with
+// the `THashList` data than reading and parsing plain text data.
+//
+// `aFilename` is the name of the file to use for reading and storing.
+func NewBinaryStorage(aFilename string) TStorage {
+	return &tBinaryStorage{tFileStorage{fn: aFilename}}
+} // NewBinaryStorage()
+
+// Save writes `aData` to the configured file returning the number
+// of bytes written and a possible error.
+//
+// If there is an error, it will be of type `*PathError`.
+//
+// (Implements `TStorage` interface)
+func (bs *tBinaryStorage) Save(aData *TStorageData) (int, error) {
+	return bs.write(func(aWriter io.Writer) error {
+		if _, err := aWriter.Write(binaryMagic); nil != err {
+			return err
+		}
+
+		encoder := gob.NewEncoder(aWriter)
+		if err := encoder.Encode(&tBinaryData{Tags: aData.Tags}); nil != err {
+			return err
+		}
+		// write the optional values up to the last non-empty one:
+		values := []interface{}{aData.Aliases, aData.Counts, aData.Seen}
+		last := 0
+		for idx, size := range []int{len(aData.Aliases), len(aData.Counts), len(aData.Seen)} {
+			if 0 < size {
+				last = idx + 1
+			}
+		}
+		for _, value := range values[:last] {
+			if err := encoder.Encode(value); nil != err {
+				return err
+			}
+		}
+
+		return nil
+	})
+} // Save()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// NewTextStorage returns a `TStorage` writing plain text data
+// to `aFilename`.
+//
+// The advantage of the plain text format is that it can be inspected
+// by any text related tool (like e.g. `diff`).
+//
+// `aFilename` is the name of the file to use for reading and storing.
+func NewTextStorage(aFilename string) TStorage {
+	return &tTextStorage{tFileStorage{fn: aFilename}}
+} // NewTextStorage()
+
+// Save writes `aData` to the configured file returning the number
+// of bytes written and a possible error.
+//
+// If there is an error, it will be of type `*PathError`.
+//
+// (Implements `TStorage` interface)
+func (ts *tTextStorage) Save(aData *TStorageData) (int, error) {
+	return ts.write(func(aWriter io.Writer) error {
+		_, err := io.WriteString(aWriter, aData.String())
+
+		return err
+	})
+} // Save()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// Close releases all resources held by the storage.
+//
+// (Implements `TStorage` interface)
+func (fs *tFileStorage) Close() error {
+	return nil
+} // Close()
+
+// Load reads the configured file returning the data read and
+// a possible error condition.
+//
+// The file's format is detected automatically, i.e. this method
+// can read files written by both, binary and text storage.
+// If the file doesn't exist that is not considered an error.
+// If the file is corrupted (or missing while its backup exists) and
+// backups are enabled the backup file is read instead and replaces
+// the list's file.
+// The modifications recorded in the journal since the file was
+// last written are returned in the result's `Journal` field.
+//
+// (Implements `TStorage` interface)
+func (fs *tFileStorage) Load() (*TStorageData, error) {
+	result, err := loadFile(fs.fn)
+	if fs.bak && fs.useBackup(err) {
+		if data, bakErr := loadFile(fs.fn + backupSuffix); nil == bakErr {
+			// Later journal entries are tied to the file's contents
+			// so it must hold the data actually loaded:
+			if err = restore(fs.fn); nil != err {
+				return result, err
+			}
+			result = data
+		}
+	}
+	if nil != err {
+		return result, err
+	}
+	result.Journal, err = fs.loadJournal(fs.fn)
+
+	return result, err
+} // Load()
+
+// `useBackup()` reports whether the backup file should be read
+// instead of the list's file.
+//
+// That's the case if the file is corrupted or if it's missing while
+// its backup exists (e.g. because saving the list was interrupted
+// after moving the file to its backup).
+//
+// `aErr` is the error returned by reading the list's file.
+func (fs *tFileStorage) useBackup(aErr error) bool {
+	if nil != aErr {
+		return !isPathError(aErr)
+	}
+	if _, err := os.Stat(fs.fn); !os.IsNotExist(err) {
+		return false
+	}
+	_, err := os.Stat(fs.fn + backupSuffix)
+
+	return nil == err
+} // useBackup()
+
+// `write()` atomically replaces the configured file by the data
+// written by `aFunc` returning the number of bytes written and a
+// possible error.
+//
+// The data is written to a temporary file in the same directory
+// which is then synced to disk and renamed to the final filename.
+// Thus the file on disk always holds either the old or the new data
+// but never a partially written mix of both.
+//
+// `aFunc` is called to write the actual data.
+func (fs *tFileStorage) write(aFunc func(aWriter io.Writer) error) (int, error) {
+	if 0 == len(fs.fn) {
+		return 0, &os.PathError{Op: "open", Path: fs.fn, Err: os.ErrNotExist}
+	}
+	dir, base := filepath.Split(fs.fn)
+	if 0 == len(dir) {
+		dir = "."
+	}
+	mode := os.FileMode(0660)
+	if fi, err := os.Stat(fs.fn); nil == err {
+		mode = fi.Mode().Perm()
+	}
+
+	tmp, err := ioutil.TempFile(dir, base+".tmp")
+	if nil != err {
+		return 0, err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if nil != err {
+			_ = tmp.Close()
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	buf := bufio.NewWriter(tmp)
+	cw := &tCountWriter{w: buf}
+	if err = aFunc(cw); nil != err {
+		return 0, err
+	}
+	if err = buf.Flush(); nil != err {
+		return 0, err
+	}
+	if err = tmp.Chmod(mode); nil != err {
+		return 0, err
+	}
+	if err = tmp.Sync(); nil != err {
+		return 0, err
+	}
+	if err = tmp.Close(); nil != err {
+		return 0, err
+	}
+	if fs.bak {
+		if err = backup(fs.fn); nil != err {
+			return 0, err
+		}
+	}
+	if err = os.Rename(tmpName, fs.fn); nil != err {
+		return 0, err
+	}
+	syncDir(dir)
+
+	// The journal's entries are part of the file now; should we
+	// crash before removing it, `loadJournal()` recognises the
+	// journal as stale by its checksum:
+	return cw.n, fs.removeJournal()
+} // write()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// Write writes `aData` to the underlying writer counting the
+// number of bytes written.
+//
+// (Implements `io.Writer` interface)
+func (cw *tCountWriter) Write(aData []byte) (int, error) {
+	n, err := cw.w.Write(aData)
+	cw.n += n
+
+	return n, err
+} // Write()
+
+// `backup()` makes the current contents of `aFilename` available
+// as its backup file.
+//
+// If `aFilename` doesn't exist that is not considered an error
+// and an existing backup file is kept.
+//
+// `aFilename` is the name of the file to backup.
+func backup(aFilename string) error {
+	if _, err := os.Stat(aFilename); nil != err {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	bakName := aFilename + backupSuffix
+	if err := os.Remove(bakName); (nil != err) && !os.IsNotExist(err) {
+		return err
+	}
+	err := os.Link(aFilename, bakName)
+	if (nil == err) || os.IsNotExist(err) {
+		return nil
+	}
+
+	// the filesystem might not support hard links:
+	return os.Rename(aFilename, bakName)
+} // backup()
+
+// `restore()` replaces `aFilename` by a copy of its backup file.
+//
+// `aFilename` is the name of the file to restore.
+func restore(aFilename string) error {
+	bak, err := os.Open(aFilename + backupSuffix)
+	if nil != err {
+		return err
+	}
+	defer bak.Close()
+	fi, err := bak.Stat()
+	if nil != err {
+		return err
+	}
+
+	dir, base := filepath.Split(aFilename)
+	if 0 == len(dir) {
+		dir = "."
+	}
+	tmp, err := ioutil.TempFile(dir, base+".tmp")
+	if nil != err {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() {
+		if nil != err {
+			_ = tmp.Close()
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err = io.Copy(tmp, bak); nil != err {
+		return err
+	}
+	if err = tmp.Chmod(fi.Mode().Perm()); nil != err {
+		return err
+	}
+	if err = tmp.Sync(); nil != err {
+		return err
+	}
+	if err = tmp.Close(); nil != err {
+		return err
+	}
+	if err = os.Rename(tmpName, aFilename); nil != err {
+		return err
+	}
+	syncDir(dir)
+
+	return nil
+} // restore()
+
+// `isPathError()` reports whether `aErr` was caused by accessing
+// the file (instead of by its contents).
+func isPathError(aErr error) bool {
+	_, ok := aErr.(*os.PathError)
+
+	return ok
+} // isPathError()
+
+// `syncDir()` flushes the directory entries of `aDir` to disk.
+//
+// Errors are ignored since not all platforms and filesystems
+// support syncing directories.
+//
+// `aDir` is the name of the directory to sync.
+func syncDir(aDir string) {
+	if dir, err := os.Open(aDir); nil == err {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+} // syncDir()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// String returns the data as a linefeed separated string.
+//
+// The result is the plain text storage format: each #hashtag/@mention
+// enclosed in brackets followed by its sorted IDs one per line (IDs
+// with more than one occurrence followed by a tab and their count,
+// IDs with timestamps followed by a tab and the time interval) and
+// finally the aliases (if any).
+func (sd *TStorageData) String() string {
+	tags := make([]string, 0, len(sd.Tags))
+	for hash := range sd.Tags {
+		tags = append(tags, hash)
+	}
+	// sort the order of hashtags to get a reproducible result
+	sort.Slice(tags, func(i, j int) bool {
+		// ignore leading [@#] when sorting
+		return (tags[i][1:] < tags[j][1:]) // ascending
+	})
+
+	var sb strings.Builder
+	for _, hash := range tags {
+		ids := append([]string(nil), sd.Tags[hash]...)
+		sort.Strings(ids)
+		sb.WriteString("[" + hash + "]\n" + idLines(ids, sd.Counts[hash], sd.Seen[hash]) + "\n")
+	}
+
+	return sb.String() + aliasString(sd.Aliases)
+} // String()
+
+// `aliasString()` returns the plain text storage format of `aAliases`:
+// each alias and its target enclosed in brackets (like `[#golang = #go]`)
+// one per line.
+func aliasString(aAliases map[string]string) string {
+	aliases := make([]string, 0, len(aAliases))
+	for alias := range aAliases {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	var sb strings.Builder
+	for _, alias := range aliases {
+		sb.WriteString("[" + alias + " = " + aAliases[alias] + "]\n")
+	}
+
+	return sb.String()
+} // aliasString()
+
+/* _EoF_ */
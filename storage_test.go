@@ -0,0 +1,291 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// `tMemStorage` is a `TStorage` keeping the data in memory.
+type tMemStorage struct {
+	data   *TStorageData
+	closed bool
+	mtx    sync.Mutex
+}
+
+func (ms *tMemStorage) Close() error {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+
+	ms.closed = true
+
+	return nil
+} // Close()
+
+// `len()` returns the number of #hashtags/@mentions saved.
+func (ms *tMemStorage) len() int {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+
+	if nil == ms.data {
+		return 0
+	}
+
+	return len(ms.data.Tags)
+} // len()
+
+func (ms *tMemStorage) Load() (*TStorageData, error) {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+
+	if nil == ms.data {
+		return newStorageData(), nil
+	}
+
+	return ms.data, nil
+} // Load()
+
+func (ms *tMemStorage) Save(aData *TStorageData) (int, error) {
+	ms.mtx.Lock()
+	defer ms.mtx.Unlock()
+
+	ms.data = aData
+
+	return len(aData.Tags), nil
+} // Save()
+
+func TestWithStorage(t *testing.T) {
+	hash1, hash2 := "#hash1", "@mention2"
+	id1, id2 := "id_c", "id_a"
+	ms := &tMemStorage{}
+	hl1, _ := New("", WithStorage(ms))
+	hl1.HashAdd(hash1, id1).
+		MentionAdd(hash2, id2).
+		HashAdd(hash1, id2)
+	if _, err := hl1.Store(); nil != err {
+		t.Errorf("THashList.Store() error = %v", err)
+	}
+	hl2, _ := New("", WithStorage(ms))
+	tests := []struct {
+		name string
+		hl   *THashList
+		want string
+	}{
+		// TODO: Add test cases.
+		{" 1", hl2, hl1.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hl.String(); got != tt.want {
+				t.Errorf("WithStorage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestWithStorage()
+
+func TestTStorage_roundtrip(t *testing.T) {
+	fn1, fn2 := delDB("storage.db"), delDB("storage.txt")
+	defer delDB(fn1)
+	defer delDB(fn2)
+	data := &TStorageData{
+		Tags: map[string][]string{
+			"#hash1":    {"id_a", "id_c"},
+			"@mention2": {"id_b"},
+		},
+	}
+	tests := []struct {
+		name string
+		st   TStorage
+	}{
+		// TODO: Add test cases.
+		{" 1", NewBinaryStorage(fn1)},
+		{" 2", NewTextStorage(fn2)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.st.Save(data); nil != err {
+				t.Errorf("TStorage.Save() error = %v", err)
+				return
+			}
+			got, err := tt.st.Load()
+			if nil != err {
+				t.Errorf("TStorage.Load() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, data) {
+				t.Errorf("TStorage.Load() = %v, want %v", got, data)
+			}
+		})
+	}
+} // TestTStorage_roundtrip()
+
+func TestWithFormat(t *testing.T) {
+	fn1, fn2 := delDB("format.db"), delDB("format.txt")
+	defer delDB(fn1)
+	defer delDB(fn2)
+	hash1, hash2 := "#hash1", "@mention2"
+	id1, id2 := "id_c", "id_a"
+	hl1, _ := New(fn1, WithFormat(FormatBinary))
+	hl1.HashAdd(hash1, id1).
+		MentionAdd(hash2, id2)
+	hl1.Store()
+	hl2, _ := New(fn2, WithFormat(FormatText))
+	hl2.HashAdd(hash1, id2).
+		MentionAdd(hash2, id1)
+	hl2.Store()
+	tests := []struct {
+		name   string
+		fn     string
+		format TFormat
+		want   string
+	}{
+		// TODO: Add test cases.
+		{" 1", fn1, FormatText, hl1.String()},
+		{" 2", fn2, FormatBinary, hl2.String()},
+		{" 3", fn1, FormatBinary, hl1.String()},
+		{" 4", fn2, FormatText, hl2.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := New(tt.fn, WithFormat(tt.format))
+			if nil != err {
+				t.Errorf("New() error = %v", err)
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("WithFormat() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestWithFormat()
+
+func Test_loadFile(t *testing.T) {
+	fn := delDB("legacy.db")
+	defer delDB(fn)
+	// write a file the way older versions of this package did:
+	file, _ := os.Create(fn)
+	gob.NewEncoder(file).Encode(tHashMap{
+		"#hash1": &tSourceList{"id_a", "id_c"},
+	})
+	file.Close()
+	wd1 := &TStorageData{
+		Tags: map[string][]string{
+			"#hash1": {"id_a", "id_c"},
+		},
+	}
+	tests := []struct {
+		name    string
+		fn      string
+		want    *TStorageData
+		wantErr bool
+	}{
+		// TODO: Add test cases.
+		{" 1", fn, wd1, false},
+		{" 2", ".does.not.exist", newStorageData(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loadFile(tt.fn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("loadFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_loadFile()
+
+func TestWithBackup(t *testing.T) {
+	fn := delDB("backup.db")
+	defer delDB(fn)
+	defer delDB(fn + backupSuffix)
+	hash1, hash2 := "#hash1", "#hash2"
+	id1, id2 := "id_c", "id_a"
+	hl1, _ := New(fn, WithBackup(true))
+	hl1.HashAdd(hash1, id1)
+	hl1.Store()
+	want := hl1.String()
+	hl1.HashAdd(hash2, id2)
+	hl1.Store()
+	// simulate a corrupted file:
+	ioutil.WriteFile(fn, []byte("garbage"), 0660)
+	hl2, err := New(fn, WithBackup(true))
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got := hl2.String(); got != want {
+		t.Errorf("WithBackup() = %v, want %v", got, want)
+	}
+	// the corrupted file is replaced by its backup:
+	if hl3, err := New(fn); (nil != err) || (hl3.String() != want) {
+		t.Errorf("New() = %v, %v, want %v", hl3, err, want)
+	}
+	// modifications after falling back to the backup are kept:
+	ioutil.WriteFile(fn, []byte("garbage"), 0660)
+	hl4, _ := New(fn, WithBackup(true))
+	hl4.HashAdd("#c", "id_b")
+	want = hl4.String()
+	hl5, err := New(fn, WithBackup(true))
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got := hl5.String(); got != want {
+		t.Errorf("WithBackup() = %v, want %v", got, want)
+	}
+	if files, _ := filepath.Glob(fn + ".tmp*"); 0 < len(files) {
+		t.Errorf("THashList.Store() left temporary files %v", files)
+	}
+} // TestWithBackup()
+
+func TestWithBackup_missingFile(t *testing.T) {
+	fn := delDB("backup.missing.db")
+	defer delDB(fn)
+	defer delDB(fn + backupSuffix)
+	hl1, _ := New(fn, WithBackup(true))
+	hl1.HashAdd("#a", "id_a").Store()
+	hl1.HashAdd("#b", "id_b")
+	want := hl1.String()
+	// simulate a crash while storing the list after moving
+	// the file to its backup:
+	if err := os.Rename(fn, fn+backupSuffix); nil != err {
+		t.Errorf("os.Rename() error = %v", err)
+		return
+	}
+	// an existing backup is kept if there's no file to backup:
+	if err := backup(fn); nil != err {
+		t.Errorf("backup() error = %v", err)
+	}
+	if _, err := os.Stat(fn + backupSuffix); nil != err {
+		t.Errorf("backup() error = %v, want %v", err, nil)
+	}
+	hl2, err := New(fn, WithBackup(true))
+	if nil != err {
+		t.Errorf("New() error = %v", err)
+		return
+	}
+	if got := hl2.String(); got != want {
+		t.Errorf("WithBackup() = %q, want %q", got, want)
+	}
+	if _, err = hl2.Store(); nil != err {
+		t.Errorf("THashList.Store() error = %v", err)
+	}
+	if hl3, _ := New(fn); hl3.String() != want {
+		t.Errorf("New() = %q, want %q", hl3.String(), want)
+	}
+} // TestWithBackup_missingFile()
+
+/* _EoF_ */
@@ -0,0 +1,368 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// TClock is the source of the timestamps recorded by a list
+	// (see `WithTimestamps()`).
+	TClock interface {
+		// Now returns the current time.
+		Now() time.Time
+	}
+
+	// TClockFunc is an adapter to use an ordinary function
+	// as a `TClock`.
+	TClockFunc func() time.Time
+
+	// TSeen holds the times a #hashtag/@mention was first and
+	// last associated with an ID.
+	//
+	// @see TagSeen(), TagSpan()
+	TSeen struct {
+		First time.Time // when the association was created
+		Last  time.Time // when the association was last confirmed
+	}
+
+	// `tSeenMap` maps list indices to IDs with their timestamps.
+	tSeenMap map[string]map[string]TSeen
+
+	// `tSystemClock` is the clock used by default.
+	tSystemClock struct{}
+)
+
+// Now calls `cf()`.
+//
+// (Implements `TClock` interface)
+func (cf TClockFunc) Now() time.Time {
+	return cf()
+} // Now()
+
+// Now returns the current local time.
+//
+// (Implements `TClock` interface)
+func (sc tSystemClock) Now() time.Time {
+	return time.Now()
+} // Now()
+
+// `merged()` returns the time span covering both `s` and `aOther`.
+func (s TSeen) merged(aOther TSeen) TSeen {
+	if s.First.IsZero() || (!aOther.First.IsZero() && aOther.First.Before(s.First)) {
+		s.First = aOther.First
+	}
+	if aOther.Last.After(s.Last) {
+		s.Last = aOther.Last
+	}
+
+	return s
+} // merged()
+
+// `overlaps()` returns whether `s` overlaps the time range from
+// `aFrom` (inclusive) to `aTo` (exclusive); a zero time means
+// the range is unbounded at that end.
+func (s TSeen) overlaps(aFrom, aTo time.Time) bool {
+	return (aTo.IsZero() || s.First.Before(aTo)) &&
+		(aFrom.IsZero() || !s.Last.Before(aFrom))
+} // overlaps()
+
+// String returns the time span as an ISO 8601 time interval
+// (i.e. both times separated by a slash).
+//
+// (Implements `Stringer` interface)
+func (s TSeen) String() string {
+	return s.First.Format(time.RFC3339Nano) + "/" + s.Last.Format(time.RFC3339Nano)
+} // String()
+
+// `parseSeen()` returns the time span represented by `aText`
+// and whether `aText` is a valid time interval.
+//
+// `aText` is a time interval written by `TSeen.String()`.
+func parseSeen(aText string) (rSeen TSeen, rOK bool) {
+	idx := strings.IndexByte(aText, '/')
+	if 0 > idx {
+		return
+	}
+	var err error
+	if rSeen.First, err = time.Parse(time.RFC3339Nano, aText[:idx]); nil != err {
+		return TSeen{}, false
+	}
+	if rSeen.Last, err = time.Parse(time.RFC3339Nano, aText[idx+1:]); nil != err {
+		return TSeen{}, false
+	}
+
+	return rSeen, true
+} // parseSeen()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `moveSeen()` transfers the timestamps of `aFromIdx` in `aFromID`
+// to `aToIdx` in `aToID` merging them with the latter's timestamps.
+func (hl *THashList) moveSeen(aFromIdx, aFromID, aToIdx, aToID string) {
+	// the mutex.Lock is done by the callers
+
+	from, ok := hl.ts[aFromIdx][aFromID]
+	if !ok {
+		return
+	}
+	hl.setSeen0(aFromIdx, aFromID, TSeen{})
+	hl.setSeen0(aToIdx, aToID, hl.ts[aToIdx][aToID].merged(from))
+} // moveSeen()
+
+// `seenIDs()` returns the sorted list of IDs of all list indices
+// accepted by `aMatch` whose association was seen in the time range
+// from `aFrom` (inclusive) to `aTo` (exclusive).
+//
+// Associations without timestamps are only accepted if both
+// `aFrom` and `aTo` are zero.
+func (hl *THashList) seenIDs(aMatch func(aMapIdx string) bool, aFrom, aTo time.Time) []string {
+	// the mutex.Lock is done by the callers
+
+	bounded := !aFrom.IsZero() || !aTo.IsZero()
+	ids := make(map[string]struct{})
+	for mapIdx, sl := range hl.hl {
+		if !aMatch(mapIdx) {
+			continue
+		}
+		for _, id := range *sl {
+			if bounded {
+				if seen, ok := hl.ts[mapIdx][id]; !ok || !seen.overlaps(aFrom, aTo) {
+					continue
+				}
+			}
+			ids[id] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(ids))
+	for id := range ids {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+
+	return result
+} // seenIDs()
+
+// `setSeen0()` sets the timestamps of `aMapIdx` in `aID` to `aSeen`;
+// zero timestamps are not stored.
+func (hl *THashList) setSeen0(aMapIdx, aID string, aSeen TSeen) {
+	// the mutex.Lock is done by the callers
+
+	if aSeen.First.IsZero() {
+		if ids, ok := hl.ts[aMapIdx]; ok {
+			delete(ids, aID)
+			if 0 == len(ids) {
+				delete(hl.ts, aMapIdx)
+			}
+		}
+		return
+	}
+	if nil == hl.ts {
+		hl.ts = make(tSeenMap)
+	}
+	ids, ok := hl.ts[aMapIdx]
+	if !ok {
+		ids = make(map[string]TSeen)
+		hl.ts[aMapIdx] = ids
+	}
+	ids[aID] = aSeen
+} // setSeen0()
+
+// `touch()` records the current time as the time `aTags` were seen
+// in `aID` if the list records timestamps (see `WithTimestamps()`).
+//
+// `aID` is the ID associated with `aTags`.
+//
+// `aTags` are the list indices (possibly including duplicates).
+func (hl *THashList) touch(aID string, aTags ...string) {
+	// the mutex.Lock is done by the callers
+
+	if (nil == hl.clk) || (0 == len(aTags)) {
+		return
+	}
+	now := hl.clk.Now().UTC()
+	for _, mapIdx := range aTags {
+		old := hl.ts[mapIdx][aID]
+		seen := old.merged(TSeen{First: now, Last: now})
+		if seen == old {
+			continue
+		}
+		hl.setSeen0(mapIdx, aID, seen)
+		atomic.StoreUint32(&hl.µChange, 0)
+		hl.journal(TJournalEntry{Op: JournalSeen, Tag: mapIdx, ID: aID,
+			Arg: seen.String()})
+	}
+} // touch()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// CountedListBetween returns a list of #hashtags/@mentions with
+// their respective count of IDs associated in the time range from
+// `aFrom` (inclusive) to `aTo` (exclusive).
+//
+// An association is counted if it was first seen before `aTo` and
+// last seen at or after `aFrom`; a zero time means the range is
+// unbounded at that end. If the list counts occurrences (see
+// `WithOccurrences()`) the count is the number of occurrences.
+//
+// `aFrom` is the start of the time range.
+//
+// `aTo` is the end of the time range.
+func (hl *THashList) CountedListBetween(aFrom, aTo time.Time) []TCountItem {
+	if aFrom.IsZero() && aTo.IsZero() {
+		return hl.CountedList()
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	result := make(tCountList, 0, len(hl.ts))
+	for mapIdx, ids := range hl.ts {
+		count := 0
+		for id, seen := range ids {
+			if !seen.overlaps(aFrom, aTo) {
+				continue
+			}
+			if hl.occ {
+				count += hl.count(mapIdx, id)
+			} else {
+				count++
+			}
+		}
+		if 0 < count {
+			result = append(result, TCountItem{count, mapIdx})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		// ignore [#@] for sorting
+		return (result[i].Tag[1:] < result[j].Tag[1:])
+	})
+
+	return result
+} // CountedListBetween()
+
+// QueryBetween returns the sorted list of IDs matching `aQuery`
+// considering only the associations seen in the time range from
+// `aFrom` (inclusive) to `aTo` (exclusive), and a possible syntax
+// error.
+//
+// An association is considered if it was first seen before `aTo`
+// and last seen at or after `aFrom`; a zero time means the range
+// is unbounded at that end. `NOT` refers to the IDs with at least
+// one association in the time range. See `Query()` for the query
+// syntax.
+//
+// `aQuery` is the query expression to evaluate.
+//
+// `aFrom` is the start of the time range.
+//
+// `aTo` is the end of the time range.
+func (hl *THashList) QueryBetween(aQuery string, aFrom, aTo time.Time) ([]string, error) {
+	return hl.query(aQuery, &tQueryEnv{from: aFrom, to: aTo})
+} // QueryBetween()
+
+// TagListBetween returns the sorted list of IDs associated with
+// `aTag` in the time range from `aFrom` (inclusive) to `aTo`
+// (exclusive).
+//
+// An association is included if it was first seen before `aTo`
+// and last seen at or after `aFrom`; a zero time means the range
+// is unbounded at that end.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+//
+// `aFrom` is the start of the time range.
+//
+// `aTo` is the end of the time range.
+func (hl *THashList) TagListBetween(aSigil byte, aTag string, aFrom, aTo time.Time) []string {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return nil
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	index := hl.alias(mapIndex(aSigil, aTag))
+	if result := hl.seenIDs(func(aMapIdx string) bool {
+		return aMapIdx == index
+	}, aFrom, aTo); 0 < len(result) {
+		return result
+	}
+
+	return nil
+} // TagListBetween()
+
+// TagSeen returns the times `aTag` was first and last associated
+// with `aID` and whether there are such timestamps.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+//
+// `aID` is the ID to lookup.
+func (hl *THashList) TagSeen(aSigil byte, aTag, aID string) (TSeen, bool) {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return TSeen{}, false
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	seen, ok := hl.ts[hl.alias(mapIndex(aSigil, aTag))][aID]
+
+	return seen, ok
+} // TagSeen()
+
+// TagSpan returns the time `aTag` was first associated with any
+// ID and the time it was last associated with any ID, and whether
+// there are such timestamps.
+//
+// `aSigil` is the tag's first character (e.g. '#', '@' or '$').
+//
+// `aTag` identifies the ID list to lookup.
+func (hl *THashList) TagSpan(aSigil byte, aTag string) (rSeen TSeen, rOK bool) {
+	if !hl.isSigil(aSigil) || (0 == len(aTag)) {
+		return
+	}
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	for _, seen := range hl.ts[hl.alias(mapIndex(aSigil, aTag))] {
+		rSeen, rOK = rSeen.merged(seen), true
+	}
+
+	return
+} // TagSpan()
+
+// WithTimestamps returns an option to record when each
+// #hashtag/@mention was first and last associated with an ID.
+//
+// The timestamps are set by all methods adding tags (e.g. `IDparse()`
+// or `HashAdd()`): adding an association again updates its last-seen
+// time. They are available by `TagSeen()` and `TagSpan()`, can be
+// used to restrict lookups to a time range (see `CountedListBetween()`,
+// `QueryBetween()` and `TagListBetween()`), and they are stored along
+// with the list.
+//
+// `aClock` provides the current time; if it's `nil` the system's
+// clock is used. Passing a clock of your own allows for reproducible
+// timestamps (e.g. in tests).
+func WithTimestamps(aClock TClock) TOption {
+	return func(aList *THashList) {
+		if nil == aClock {
+			aClock = tSystemClock{}
+		}
+		aList.clk = aClock
+	}
+} // WithTimestamps()
+
+/* _EoF_ */
@@ -0,0 +1,371 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// `tsDay()` returns noon of the given day in October 2026.
+func tsDay(aDay int) time.Time {
+	return time.Date(2026, 10, aDay, 12, 0, 0, 0, time.UTC)
+} // tsDay()
+
+func Test_parseSeen(t *testing.T) {
+	tests := []struct {
+		name   string
+		aText  string
+		want   TSeen
+		wantOK bool
+	}{
+		{" 1", "2026-10-01T12:00:00Z/2026-10-03T12:00:00Z", TSeen{tsDay(1), tsDay(3)}, true},
+		{" 2", TSeen{tsDay(1), tsDay(5).Add(time.Nanosecond)}.String(), TSeen{tsDay(1), tsDay(5).Add(time.Nanosecond)}, true},
+		{" 3", "2026-10-01T12:00:00Z", TSeen{}, false},
+		{" 4", "2026-10-01/2026-10-03", TSeen{}, false},
+		{" 5", "", TSeen{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := parseSeen(tt.aText)
+			if gotOK != tt.wantOK {
+				t.Errorf("parseSeen() OK = %v, want %v", gotOK, tt.wantOK)
+			}
+			if !got.First.Equal(tt.want.First) || !got.Last.Equal(tt.want.Last) {
+				t.Errorf("parseSeen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_parseSeen()
+
+func TestTHashList_TagSeen(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		aID    string
+		want   TSeen
+		wantOK bool
+	}{
+		{" 1", '#', "go", "id_a", TSeen{tsDay(1), tsDay(5)}, true},
+		{" 2", '#', "GO", "id_b", TSeen{tsDay(3), tsDay(3)}, true},
+		{" 3", '@', "alice", "id_d", TSeen{tsDay(8), tsDay(8)}, true},
+		{" 4", '#', "rust", "id_c", TSeen{tsDay(5), tsDay(5)}, true},
+		{" 5", '@', "alice", "id_b", TSeen{}, false},
+		{" 6", '#', "unknown", "id_a", TSeen{}, false},
+		{" 7", '!', "go", "id_a", TSeen{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := hl.TagSeen(tt.aSigil, tt.aTag, tt.aID)
+			if gotOK != tt.wantOK {
+				t.Errorf("THashList.TagSeen() OK = %v, want %v", gotOK, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("THashList.TagSeen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_TagSeen()
+
+func TestTHashList_TagSpan(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		want   TSeen
+		wantOK bool
+	}{
+		{" 1", '@', "alice", TSeen{tsDay(1), tsDay(8)}, true},
+		{" 2", '#', "go", TSeen{tsDay(1), tsDay(5)}, true},
+		{" 3", '#', "rust", TSeen{tsDay(3), tsDay(5)}, true},
+		{" 4", '#', "unknown", TSeen{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotOK := hl.TagSpan(tt.aSigil, tt.aTag)
+			if gotOK != tt.wantOK {
+				t.Errorf("THashList.TagSpan() OK = %v, want %v", gotOK, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("THashList.TagSpan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_TagSpan()
+
+func TestTHashList_TagListBetween(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	var zero time.Time
+	tests := []struct {
+		name   string
+		aSigil byte
+		aTag   string
+		aFrom  time.Time
+		aTo    time.Time
+		want   []string
+	}{
+		{" 1", '#', "go", tsDay(4), tsDay(6), []string{"id_a"}},
+		{" 2", '@', "alice", tsDay(5), zero, []string{"id_c", "id_d"}},
+		{" 3", '@', "alice", zero, tsDay(5), []string{"id_a"}},
+		{" 4", '#', "rust", tsDay(6), tsDay(9), nil},
+		{" 5", '#', "go", zero, zero, []string{"id_a", "id_b"}},
+		{" 6", '#', "unknown", zero, zero, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.TagListBetween(tt.aSigil, tt.aTag, tt.aFrom, tt.aTo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.TagListBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_TagListBetween()
+
+func TestTHashList_QueryBetween(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	var zero time.Time
+	tests := []struct {
+		name    string
+		aQuery  string
+		aFrom   time.Time
+		aTo     time.Time
+		want    []string
+		wantErr bool
+	}{
+		{" 1", "#go OR #rust", tsDay(4), tsDay(6), []string{"id_a", "id_c"}, false},
+		{" 2", "@alice NOT #rust", tsDay(5), zero, []string{"id_d"}, false},
+		{" 3", "NOT #go", tsDay(4), tsDay(9), []string{"id_c", "id_d"}, false},
+		{" 4", "#go", zero, zero, []string{"id_a", "id_b"}, false},
+		{" 5", "#go @alice", tsDay(2), zero, []string{}, false},
+		{" 6", "#go (", zero, zero, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hl.QueryBetween(tt.aQuery, tt.aFrom, tt.aTo)
+			if (nil != err) != tt.wantErr {
+				t.Errorf("THashList.QueryBetween() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.QueryBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_QueryBetween()
+
+func TestTHashList_CountedListBetween(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock), WithOccurrences(true))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	var zero time.Time
+	tests := []struct {
+		name  string
+		aFrom time.Time
+		aTo   time.Time
+		want  []TCountItem
+	}{
+		{" 1", tsDay(4), tsDay(6), []TCountItem{{1, "@alice"}, {1, "#go"}, {2, "#rust"}}},
+		{" 2", tsDay(1), tsDay(2), []TCountItem{{1, "@alice"}, {1, "#go"}}},
+		{" 3", tsDay(9), zero, []TCountItem{}},
+		{" 4", zero, zero, []TCountItem{{3, "@alice"}, {2, "#go"}, {3, "#rust"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.CountedListBetween(tt.aFrom, tt.aTo); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.CountedListBetween() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTHashList_CountedListBetween()
+
+func TestTHashList_timestampChanges(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	hl.IDparse("id_a", []byte("#go @alice"))
+	now = tsDay(3)
+	hl.IDparse("id_b", []byte("#go #rust"))
+	now = tsDay(5)
+	hl.HashAdd("#go", "id_a").
+		IDparse("id_c", []byte("@alice #rust #rust"))
+	now = tsDay(8)
+	hl.MentionAdd("alice", "id_d")
+	now = tsDay(9)
+	hl.IDparse("id_b", []byte("#rust"))
+	tests := []struct {
+		name   string
+		fn     func()
+		aSigil byte
+		aTag   string
+		aID    string
+		want   TSeen
+		wantOK bool
+	}{
+		{" 1", func() { hl.IDrename("id_a", "id_x") }, '#', "go", "id_x", TSeen{tsDay(1), tsDay(5)}, true},
+		{" 2", func() {}, '#', "go", "id_a", TSeen{}, false},
+		{" 3", func() { hl.MergeTags("#rust", "#go") }, '#', "go", "id_b", TSeen{tsDay(3), tsDay(9)}, true},
+		{" 4", func() { hl.AliasAdd("@alice", "#go") }, '#', "go", "id_d", TSeen{tsDay(8), tsDay(8)}, true},
+		{" 5", func() { hl.HashRemove("#go", "id_d") }, '#', "go", "id_d", TSeen{}, false},
+		{" 6", func() { hl.HashAdd("#go", "id_d") }, '#', "go", "id_d", TSeen{tsDay(9), tsDay(9)}, true},
+		{" 7", func() { hl.TagRename("#go", "#golang") }, '#', "golang", "id_c", TSeen{tsDay(5), tsDay(5)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn()
+			got, gotOK := hl.TagSeen(tt.aSigil, tt.aTag, tt.aID)
+			if gotOK != tt.wantOK {
+				t.Errorf("THashList.TagSeen() OK = %v, want %v", gotOK, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("THashList.TagSeen() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if hl.TagDelete("#golang"); 0 != len(hl.ts) {
+		t.Errorf("THashList.TagDelete() = %v, want %v", hl.ts, nil)
+	}
+} // TestTHashList_timestampChanges()
+
+func TestTHashList_timestampStorage(t *testing.T) {
+	fn1, fn2, fn3 := delDB("timestamp.db"), delDB("timestamp.txt"), delDB("timestamp.journal.db")
+	defer delDB(fn1)
+	defer delDB(fn2)
+	defer delDB(fn3)
+	tests := []struct {
+		name  string
+		fn    string
+		opts  []TOption
+		store bool
+	}{
+		{" 1", fn1, []TOption{WithFormat(FormatBinary)}, true},
+		{" 2", fn2, []TOption{WithFormat(FormatText)}, true},
+		{" 3", fn3, nil, false},
+	}
+	wantStr := "[@alice]\nid_a\t2026-10-01T12:00:00Z/2026-10-01T12:00:00Z\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := tsDay(1)
+			clock := TClockFunc(func() time.Time {
+				return now
+			})
+			hl1, _ := New(tt.fn, append(tt.opts, WithTimestamps(clock), WithOccurrences(true))...)
+			hl1.IDparse("id_a", []byte("#go @alice"))
+			now = tsDay(3)
+			hl1.IDparse("id_b", []byte("#go #rust"))
+			now = tsDay(5)
+			hl1.HashAdd("#go", "id_a").
+				IDparse("id_c", []byte("@alice #rust #rust"))
+			now = tsDay(8)
+			hl1.MentionAdd("alice", "id_d")
+			if got := hl1.String(); got[:len(wantStr)] != wantStr {
+				t.Errorf("THashList.String() = %q, want prefix %q", got, wantStr)
+			}
+			if tt.store {
+				if _, err := hl1.Store(); nil != err {
+					t.Errorf("THashList.Store() error = %v", err)
+					return
+				}
+			} else if err := hl1.Flush(); nil != err {
+				t.Errorf("THashList.Flush() error = %v", err)
+				return
+			}
+			hl2, err := New(tt.fn, append(tt.opts, WithOccurrences(true), WithTimestamps(nil))...)
+			if nil != err {
+				t.Errorf("New() error = %v", err)
+				return
+			}
+			if got, want := hl2.String(), hl1.String(); got != want {
+				t.Errorf("New() = %q, want %q", got, want)
+			}
+			if got, _ := hl2.TagSeen('#', "go", "id_a"); got != (TSeen{tsDay(1), tsDay(5)}) {
+				t.Errorf("THashList.TagSeen() = %v, want %v", got, TSeen{tsDay(1), tsDay(5)})
+			}
+		})
+	}
+} // TestTHashList_timestampStorage()
+
+func TestWithTimestamps(t *testing.T) {
+	hl, _ := New("")
+	hl.IDparse("id_a", []byte("#go"))
+	if _, ok := hl.TagSeen('#', "go", "id_a"); ok {
+		t.Errorf("THashList.TagSeen() OK = %v, want %v", ok, false)
+	}
+	if got, want := hl.String(), "[#go]\nid_a\n"; got != want {
+		t.Errorf("THashList.String() = %q, want %q", got, want)
+	}
+	before := time.Now()
+	hl, _ = New("", WithTimestamps(nil))
+	hl.IDparse("id_a", []byte("#go"))
+	if got, ok := hl.TagSeen('#', "go", "id_a"); !ok || got.First.Before(before.Add(-time.Second)) {
+		t.Errorf("THashList.TagSeen() = %v, want after %v", got, before)
+	}
+} // TestWithTimestamps()
+
+/* _EoF_ */
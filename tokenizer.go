@@ -0,0 +1,299 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type (
+	// TTokenizer is the interface of the text analysers used by
+	// `THashList` to extract #hashtags and @mentions from a text
+	// (see `IDparse()` and `IDupdate()`).
+	//
+	// Implementations must be safe for concurrent use.
+	TTokenizer interface {
+		// Tokenize returns the #hashtags and @mentions found in
+		// `aText` (including their leading '#' or '@').
+		//
+		// The returned words are normalised by the list (e.g.
+		// lower-cased); words not starting with one of the list's
+		// sigils (i.e. '#', '@' or those registered by `WithSigil()`)
+		// are ignored. Duplicates are allowed.
+		Tokenize(aText []byte) []string
+	}
+
+	// TTokenizerFunc is an adapter to use an ordinary function
+	// as a `TTokenizer`.
+	TTokenizerFunc func(aText []byte) []string
+
+	// `tTextTokenizer` is the default tokenizer handling plain text.
+	tTextTokenizer struct {
+		sigils []TSigil // kinds of tags to find (nil: the default)
+	}
+)
+
+// Tokenize calls `tf(aText)`.
+//
+// (Implements `TTokenizer` interface)
+func (tf TTokenizerFunc) Tokenize(aText []byte) []string {
+	return tf(aText)
+} // Tokenize()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+var (
+	// RegEx to identify a numeric HTML entity.
+	entityRE = regexp.MustCompile(`(#[0-9]+;)`)
+
+	// match: hex colour literal
+	hexColourRE = regexp.MustCompile(`^#(?:[0-9A-Fa-f]{3,4}|[0-9A-Fa-f]{6}|[0-9A-Fa-f]{8})$`)
+
+	// match: URL (with scheme or starting with `www.`)
+	urlRE = regexp.MustCompile(`(?i)\b(?:[a-z][a-z0-9+.-]*://|mailto:|www\.)[^\s<>"'()\[\]{}]+`)
+)
+
+// Characters not allowed directly before a #hashtag/@mention.
+const tagNoPrefix = "/.:&=?%~+@#"
+
+// `isHexColour()` returns whether `aHash` is a colour literal like
+// `#fff`, `#c0ffee` or `#ff000080`.
+//
+// To not reject words like `#cafe` or `#facade` (or numbers like
+// `#2024`) the hex digits must include both a decimal digit and a
+// letter unless they consist of a single repeated character (like
+// `#fff` or `#000000`). Since short tags like `#B2B` or `#A380` are
+// common in plain text, literals of three or four mixed digits are
+// only taken as colours in a CSS-like context (see `isCSSContext()`).
+//
+// `aHash` is the #hashtag to check.
+//
+// `aCSS` tells whether `aHash` appears in a CSS-like context.
+func isHexColour(aHash string, aCSS bool) bool {
+	if !hexColourRE.MatchString(aHash) {
+		return false
+	}
+	if 0 == len(strings.Trim(aHash[1:], aHash[1:2])) {
+		return true
+	}
+	digits := strings.IndexAny(aHash, "0123456789")
+	letters := strings.IndexAny(aHash, "abcdefABCDEF")
+
+	return (0 < digits) && (0 < letters) && (aCSS || (5 < len(aHash)))
+} // isHexColour()
+
+// `isCSSContext()` returns whether the word from `aStart` to `aEnd`
+// in `aText` appears in a CSS-like context, i.e. it follows a colon
+// or is followed by a semicolon (like in `color: #c64;`).
+//
+// Commas and parentheses are common around tags in plain text
+// (like in `(#B2B, #C64)`) and hence don't count.
+func isCSSContext(aText []byte, aStart, aEnd int) bool {
+	before := bytes.TrimRight(aText[:aStart], " \t")
+	if (0 < len(before)) && (':' == before[len(before)-1]) {
+		return true
+	}
+
+	return (aEnd < len(aText)) && (';' == aText[aEnd])
+} // isCSSContext()
+
+// `isTagStart()` returns whether a #hashtag/@mention may start at
+// position `aPos` of `aText`.
+//
+// A tag must not directly follow a letter, number or mark (as in
+// `user@example.com` or `page#section`) nor one of the characters
+// used within URLs or entities (like `/`, `&` or `=`).
+func isTagStart(aText []byte, aPos int) bool {
+	if 0 == aPos {
+		return true
+	}
+	r, _ := utf8.DecodeLastRune(aText[:aPos])
+	if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsMark(r) {
+		return false
+	}
+
+	return !strings.ContainsRune(tagNoPrefix, r)
+} // isTagStart()
+
+// `textMatches()` returns the tags found in `aText` along with
+// their positions.
+//
+// The following words are not considered as tags:
+//
+//   - anything within an URL (like `https://site/page#section`
+//     or `https://social.example/@user`),
+//   - email addresses (like `user@example.com`) and other words
+//     with a sigil following a letter, number or one of the
+//     characters `/.:&=?%~+@#`,
+//   - HTML entities (like `&#39;`) and URL fragments followed by
+//     a double quote (as in `href="page#section"`),
+//   - hex colour literals (see `isHexColour()`).
+//
+// `aText` is the plain text to search.
+//
+// `aSigils` are the kinds of tags to look for.
+func textMatches(aText []byte, aSigils []TSigil) (rList []TMatch) {
+	var urls [][]int
+	if 0 <= bytes.Index(aText, []byte(":")) || 0 <= bytes.Index(aText, []byte("www.")) {
+		urls = urlRE.FindAllIndex(aText, -1)
+	}
+	for pos := 0; pos < len(aText); {
+		sigil := findSigil(aSigils, aText[pos])
+		if (nil == sigil) || !isTagStart(aText, pos) {
+			pos++
+			continue
+		}
+		start, end := pos, pos+1
+		for end < len(aText) {
+			r, size := utf8.DecodeRune(aText[end:])
+			if (start+1 < end) && (0 != sigil.sep) && (rune(sigil.sep) == r) {
+				if sigil.sep == aText[end-1] {
+					break // empty level
+				}
+				end += size
+				continue
+			}
+			if ((start+1 == end) && !sigil.first(r)) ||
+				((start+1 < end) && !sigil.next(r)) {
+				break
+			}
+			end += size
+		}
+		if pos = end; start+1 == end {
+			// a lone sigil
+			continue
+		}
+		for (0 < len(urls)) && (urls[0][1] <= start) {
+			urls = urls[1:]
+		}
+		if (0 < len(urls)) && (urls[0][0] <= start) {
+			// part of an URL
+			continue
+		}
+		// '_' can be both, part of the hashtag and italic markup
+		// so we must remove it if it's at the end; the zero width
+		// joiners and hierarchy separators are only allowed inside
+		// of a tag:
+		cutset := "_\u200C\u200D"
+		if 0 != sigil.sep {
+			cutset += string(sigil.sep)
+		}
+		tag := strings.TrimRight(string(aText[start:end]), cutset)
+		if 2 > utf8.RuneCountInString(tag) {
+			continue
+		}
+		if '#' == tag[0] {
+			if end < len(aText) {
+				if '"' == aText[end] {
+					// double quote following a possible hashtag: most
+					// probably an URL#fragment, hence leave it as is
+					continue
+				}
+				if (';' == aText[end]) && entityRE.MatchString(tag+";") {
+					// leave HTML entities as is
+					continue
+				}
+			}
+			if isHexColour(tag, isCSSContext(aText, start, start+len(tag))) {
+				continue
+			}
+		}
+		rList = append(rList, TMatch{
+			Tag:    tag,
+			Offset: start,
+			Length: len(tag),
+		})
+	}
+
+	return
+} // textMatches()
+
+// Match returns the #hashtags and @mentions found in `aText`
+// along with their positions.
+//
+// (Implements `TMatcher` interface)
+func (tt tTextTokenizer) Match(aText []byte) []TMatch {
+	return setRuneOffsets(aText, textMatches(aText, sigilsOf(tt.sigils)))
+} // Match()
+
+// Tokenize returns the #hashtags and @mentions found in `aText`
+// (see `textMatches()` for the words not considered as tags).
+//
+// (Implements `TTokenizer` interface)
+func (tt tTextTokenizer) Tokenize(aText []byte) []string {
+	return matchTags(textMatches(aText, sigilsOf(tt.sigils)))
+} // Tokenize()
+
+// `withSigils()` returns a copy of the tokenizer using `aSigils`.
+func (tt tTextTokenizer) withSigils(aSigils []TSigil) TTokenizer {
+	return tTextTokenizer{sigils: aSigils}
+} // withSigils()
+
+// NewTextTokenizer returns the tokenizer used by default which
+// handles plain text.
+func NewTextTokenizer() TTokenizer {
+	return tTextTokenizer{}
+} // NewTextTokenizer()
+
+/* * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * */
+
+// `parseTags()` returns the list indices of all #hashtags/@mentions
+// found in `aText` and accepted by the list's filter.
+//
+// `aID` is the ID the tags are to be added to.
+//
+// `aText` is the text to search.
+func (hl *THashList) parseTags(aID string, aText []byte) (rList []string) {
+	// the mutex.Lock is done by the callers
+
+	for _, word := range hl.tokenizer().Tokenize(aText) {
+		if (1 < len(word)) && hl.isSigil(word[0]) {
+			if mapIdx := mapIndex(word[0], word); hl.accept(mapIdx, aID) {
+				rList = append(rList, hl.alias(mapIdx))
+			}
+		}
+	}
+
+	return
+} // parseTags()
+
+// `tokenizer()` returns the list's tokenizer.
+//
+// The tokenizers of this package are set up to find all sigils
+// registered with the list.
+func (hl *THashList) tokenizer() TTokenizer {
+	tok := hl.tok
+	if nil == tok {
+		tok = tTextTokenizer{}
+	}
+	if (0 < len(hl.sigils)) || (0 != hl.sep) {
+		if st, ok := tok.(tSigilTokenizer); ok {
+			return st.withSigils(hl.tokenSigils())
+		}
+	}
+
+	return tok
+} // tokenizer()
+
+// WithTokenizer returns an option to use `aTokenizer` for extracting
+// #hashtags and @mentions in `IDparse()` and `IDupdate()` instead of
+// the default plain text tokenizer.
+//
+// `aTokenizer` is the text analyser to use.
+func WithTokenizer(aTokenizer TTokenizer) TOption {
+	return func(aList *THashList) {
+		aList.tok = aTokenizer
+	}
+} // WithTokenizer()
+
+/* _EoF_ */
@@ -0,0 +1,94 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_tTextTokenizer_Tokenize(t *testing.T) {
+	tok := NewTextTokenizer()
+	tests := []struct {
+		name  string
+		aText string
+		want  []string
+	}{
+		// TODO: Add test cases.
+		{" 0", "no tags at all", nil},
+		{" 1", "#One and @Two", []string{"#One", "@Two"}},
+		{" 2", "_#italic_ text", []string{"#italic"}},
+		{" 3", `<a href="page#fragment">`, nil},
+		{" 4", "an entity &#39; here", nil},
+		{" 5", "#one #one", []string{"#one", "#one"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Tokenize([]byte(tt.aText)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tTextTokenizer.Tokenize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // Test_tTextTokenizer_Tokenize()
+
+func Test_tTextTokenizer_Unicode(t *testing.T) {
+	tok := NewTextTokenizer()
+	tests := []struct {
+		name  string
+		aText string
+		want  []string
+	}{
+		// TODO: Add test cases.
+		{"latin", "Un #café, s'il vous plaît", []string{"#café"}},
+		{"german", "#Übergrößenträger und #Straße!", []string{"#Übergrößenträger", "#Straße"}},
+		{"combining", "#nai\u0308ve and #naïve", []string{"#nai\u0308ve", "#naïve"}},
+		{"cjk", "東京で #日本語 を勉強する", []string{"#日本語"}},
+		{"hangul", "#한국어。", []string{"#한국어"}},
+		{"cyrillic", "Привет, #Москва! @Пётр", []string{"#Москва", "@Пётр"}},
+		{"arabic", "مرحبا #العربية و #مرحباً", []string{"#العربية", "#مرحباً"}},
+		{"digits", "#٢٠٢٤ and #2024年", []string{"#٢٠٢٤", "#2024年"}},
+		{"devanagari", "#हिन्दी text", []string{"#हिन्दी"}},
+		{"zwnj", "#می\u200cخواهم \u200c#x\u200c", []string{"#می\u200cخواهم", "#x"}},
+		{"mark first", "#\u0308abc", nil},
+		{"punctuation", "(#tag1) «#tag2» #tag3…", []string{"#tag1", "#tag2", "#tag3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tok.Tokenize([]byte(tt.aText)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tTextTokenizer.Tokenize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+} // Test_tTextTokenizer_Unicode()
+
+func TestWithTokenizer(t *testing.T) {
+	// a tokenizer accepting `+word` as #hashtag (e.g. for chat logs)
+	tok := TTokenizerFunc(func(aText []byte) (rList []string) {
+		for _, word := range strings.Fields(string(aText)) {
+			if strings.HasPrefix(word, "+") {
+				rList = append(rList, "#"+word[1:])
+			} else {
+				rList = append(rList, word)
+			}
+		}
+		return
+	})
+	hl, _ := New("", WithTokenizer(tok))
+
+	hl.IDparse("id1", []byte("+Go is fun #really @bob"))
+	if got, want := hl.IDlist("id1"), []string{"#go", "#really", "@bob"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDparse() = %v, want %v", got, want)
+	}
+
+	hl.IDupdate("id1", []byte("+Rust only"))
+	if got, want := hl.IDlist("id1"), []string{"#rust"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IDupdate() = %v, want %v", got, want)
+	}
+} // TestWithTokenizer()
+
+/* _EoF_ */
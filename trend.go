@@ -0,0 +1,171 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+//lint:file-ignore ST1017 - I prefer Yoda conditions
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+type (
+	// TTrendItem holds a #hashtag/@mention and its trend.
+	//
+	// @see Trending()
+	TTrendItem struct {
+		Count int     // number of associations new in the recent window
+		Tag   string  // name of #hashtag/@mention
+		Score float64 // growth relative to the baseline (highest first)
+		Delta float64 // (decayed) recent activity minus the expected one
+	}
+
+	// TTrendWindow configures the time windows used by `Trending()`.
+	//
+	// The number of new associations of each #hashtag/@mention in
+	// the recent window (i.e. the period of length `Window` ending
+	// at `Now`) is compared with its average number in the baseline
+	// period preceding the recent window.
+	TTrendWindow struct {
+		// Now is the end of the recent window; if it's zero the
+		// list's clock is used (see `WithTimestamps()`).
+		Now time.Time
+
+		// Window is the length of the recent window; if it's zero
+		// a day is used.
+		Window time.Duration
+
+		// Baseline is the length of the baseline period; if it's
+		// zero seven times `Window` is used.
+		Baseline time.Duration
+
+		// HalfLife is the age after which the activity in the
+		// recent window counts half; if it's zero half of `Window`
+		// is used, a negative value disables the decay.
+		HalfLife time.Duration
+	}
+)
+
+// `defaults()` returns the trend window with all zero values
+// replaced by their defaults.
+//
+// `aNow` is the time to use if `tw.Now` is zero.
+func (tw TTrendWindow) defaults(aNow time.Time) TTrendWindow {
+	if tw.Now.IsZero() {
+		tw.Now = aNow
+	}
+	if 0 >= tw.Window {
+		tw.Window = 24 * time.Hour
+	}
+	if 0 >= tw.Baseline {
+		tw.Baseline = 7 * tw.Window
+	}
+	if 0 == tw.HalfLife {
+		tw.HalfLife = tw.Window / 2
+	}
+
+	return tw
+} // defaults()
+
+// `weight()` returns the decayed weight of activity at `aTime`.
+func (tw TTrendWindow) weight(aTime time.Time) float64 {
+	if 0 > tw.HalfLife {
+		return 1
+	}
+
+	return math.Exp2(-float64(tw.Now.Sub(aTime)) / float64(tw.HalfLife))
+} // weight()
+
+// `avgWeight()` returns the average decayed weight of activity
+// spread evenly across the recent window.
+func (tw TTrendWindow) avgWeight() float64 {
+	if 0 > tw.HalfLife {
+		return 1
+	}
+	// the integral of `weight()` over the window divided by its length:
+	span := float64(tw.Window) / float64(tw.HalfLife)
+
+	return (1 - math.Exp2(-span)) / (span * math.Ln2)
+} // avgWeight()
+
+// Trending returns the #hashtags/@mentions whose activity grew most
+// in the recent window compared to their baseline (highest first).
+//
+// The activity of a tag consists of the times its associations were
+// first seen (see `WithTimestamps()`), so re-saving an existing ID
+// doesn't count as growth; the recent activity is decayed
+// exponentially by its age. `Delta` is the (decayed) recent
+// activity minus the (equally decayed) activity expected at the
+// baseline's average rate, and `Score` is `Delta` relative to the
+// square root of that expectation, so that both new tags and growing
+// established ones are ranked. Only tags with a positive `Delta` are returned.
+//
+// Without timestamps the result is empty.
+//
+// `aWindow` configures the recent window and the baseline period.
+//
+// `aLimit` is the maximal number of tags to return (zero or less
+// returns all trending tags).
+func (hl *THashList) Trending(aWindow TTrendWindow, aLimit int) []TTrendItem {
+	hl.mtx.RLock()
+	defer hl.mtx.RUnlock()
+
+	if nil == hl.clk {
+		return nil
+	}
+	tw := aWindow.defaults(hl.clk.Now())
+	start := tw.Now.Add(-tw.Window)
+	baseStart := start.Add(-tw.Baseline)
+	// the baseline's activity is decayed like the recent one
+	// would be at the same rate:
+	ratio := float64(tw.Window) / float64(tw.Baseline) * tw.avgWeight()
+
+	result := make([]TTrendItem, 0, len(hl.ts))
+	for mapIdx, ids := range hl.ts {
+		var (
+			count       int
+			recent, old float64
+		)
+		for _, seen := range ids {
+			// re-saving an ID only moves `Last`, so just the
+			// first sighting counts as activity:
+			if seen.First.Before(baseStart) || seen.First.After(tw.Now) {
+				continue
+			}
+			if seen.First.Before(start) {
+				old++
+			} else {
+				recent += tw.weight(seen.First)
+				count++
+			}
+		}
+		expected := old * ratio
+		if delta := recent - expected; 0 < delta {
+			result = append(result, TTrendItem{
+				Count: count,
+				Tag:   mapIdx,
+				Score: delta / math.Sqrt(expected+1),
+				Delta: delta,
+			})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Score != result[j].Score {
+			return result[i].Score > result[j].Score
+		}
+		// ignore [#@] for sorting
+		return (result[i].Tag[1:] < result[j].Tag[1:])
+	})
+	if (0 < aLimit) && (aLimit < len(result)) {
+		result = result[:aLimit]
+	}
+
+	return result
+} // Trending()
+
+/* _EoF_ */
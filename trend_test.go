@@ -0,0 +1,168 @@
+/*
+   Copyright © 2019 M.Watermann, 10247 Berlin, Germany
+                  All rights reserved
+              EMail : <support@mwat.de>
+*/
+
+package hashtags
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestTTrendWindow_defaults(t *testing.T) {
+	now := tsDay(8)
+	tests := []struct {
+		name string
+		tw   TTrendWindow
+		want TTrendWindow
+	}{
+		{" 1", TTrendWindow{}, TTrendWindow{now, 24 * time.Hour, 168 * time.Hour, 12 * time.Hour}},
+		{" 2", TTrendWindow{Window: time.Hour}, TTrendWindow{now, time.Hour, 7 * time.Hour, 30 * time.Minute}},
+		{" 3", TTrendWindow{tsDay(1), time.Hour, 2 * time.Hour, -1}, TTrendWindow{tsDay(1), time.Hour, 2 * time.Hour, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tw.defaults(now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TTrendWindow.defaults() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+} // TestTTrendWindow_defaults()
+
+func TestTHashList_Trending(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	for day := 1; 8 >= day; day++ {
+		now = tsDay(day).Add(-2 * time.Hour)
+		d := strconv.Itoa(day)
+		hl.HashAdd("#steady", "s"+d)
+		if 7 >= day {
+			hl.HashAdd("#warm", "w"+d)
+		}
+		if 2 >= day {
+			hl.MentionAdd("@old", "o"+d)
+		}
+	}
+	// the last day's (i.e. the recent window's) activity:
+	hl.IDparse("h1", []byte("#hot #warm")).
+		IDparse("h2", []byte("#hot #warm")).
+		IDparse("h3", []byte("#hot #warm #steady"))
+	// re-saving an old ID doesn't count:
+	hl.HashAdd("#warm", "w1")
+	// `Trending()` uses the list's clock by default:
+	now = tsDay(8)
+	tests := []struct {
+		name    string
+		aWindow TTrendWindow
+		aLimit  int
+		want    []TTrendItem
+	}{
+		{" 1", TTrendWindow{HalfLife: -1}, 0, []TTrendItem{
+			{3, "#hot", 3, 3},
+			{3, "#warm", 2 / math.Sqrt(2), 2},
+			{2, "#steady", 1 / math.Sqrt(2), 1},
+		}},
+		{" 2", TTrendWindow{HalfLife: -1}, 2, []TTrendItem{
+			{3, "#hot", 3, 3},
+			{3, "#warm", 2 / math.Sqrt(2), 2},
+		}},
+		{" 3", TTrendWindow{Now: tsDay(3), HalfLife: -1}, 0, []TTrendItem{
+			{1, "#steady", (1 - 2*(1.0/7)) / math.Sqrt(2*(1.0/7)+1), 1 - 2*(1.0/7)},
+			{1, "#warm", (1 - 2*(1.0/7)) / math.Sqrt(2*(1.0/7)+1), 1 - 2*(1.0/7)},
+		}},
+		{" 4", TTrendWindow{Now: tsDay(20)}, 0, []TTrendItem{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := hl.Trending(tt.aWindow, tt.aLimit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("THashList.Trending() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// the decay lowers the recent activity:
+	got := hl.Trending(TTrendWindow{}, 1)
+	weight := math.Exp2(-2.0 / 12.0)
+	if (1 != len(got)) || ("#hot" != got[0].Tag) ||
+		(1e-9 < math.Abs(got[0].Delta-3*weight)) {
+		t.Errorf("THashList.Trending() = %v, want Delta %v", got, 3*weight)
+	}
+
+	hl, _ = New("")
+	if got := hl.HashAdd("#hot", "id_a").Trending(TTrendWindow{}, 0); nil != got {
+		t.Errorf("THashList.Trending() = %v, want %v", got, nil)
+	}
+} // TestTHashList_Trending()
+
+func TestTHashList_TrendingResaved(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	for i := 0; 10 > i; i++ {
+		hl.IDparse("e"+strconv.Itoa(i), []byte("#evergreen"))
+	}
+	// all old posts are re-saved today, two new ones are written:
+	now = tsDay(8).Add(-2 * time.Hour)
+	for i := 0; 10 > i; i++ {
+		hl.IDupdate("e"+strconv.Itoa(i), []byte("#evergreen, updated"))
+	}
+	hl.IDparse("f1", []byte("#fresh")).
+		IDparse("f2", []byte("#fresh"))
+	now = tsDay(8)
+
+	want := []TTrendItem{{2, "#fresh", 2, 2}}
+	if got := hl.Trending(TTrendWindow{HalfLife: -1}, 0); !reflect.DeepEqual(got, want) {
+		t.Errorf("THashList.Trending() = %v, want %v", got, want)
+	}
+} // TestTHashList_TrendingResaved()
+
+func TestTHashList_TrendingDecay(t *testing.T) {
+	now := tsDay(1)
+	clock := TClockFunc(func() time.Time {
+		return now
+	})
+	hl, _ := New("", WithTimestamps(clock))
+	tw := TTrendWindow{Now: tsDay(8)}.defaults(now)
+	start := tw.Now.Add(-tw.Window)
+	// `aPerHour` new IDs for `aTag` in each hour from `aFrom` to `aTo`:
+	post := func(aTag string, aFrom, aTo time.Time, aPerHour int) {
+		for hour := aFrom; hour.Before(aTo); hour = hour.Add(time.Hour) {
+			for i := 0; aPerHour > i; i++ {
+				now = hour.Add(time.Duration(2*i+1) * time.Hour / time.Duration(2*aPerHour))
+				hl.HashAdd(aTag, aTag[1:]+now.Format(time.RFC3339Nano))
+			}
+		}
+	}
+	for _, tag := range []string{"#steady", "#growing", "#doubled"} {
+		post(tag, start.Add(-tw.Baseline), start, 2)
+	}
+	post("#steady", start, tw.Now, 2)
+	post("#growing", start, tw.Now, 3)
+	post("#doubled", start, tw.Now, 4)
+
+	// the default `HalfLife` decays the recent activity:
+	got := hl.Trending(TTrendWindow{Now: tw.Now}, 0)
+	if (2 != len(got)) || ("#doubled" != got[0].Tag) || ("#growing" != got[1].Tag) {
+		t.Errorf("THashList.Trending() = %v, want %v", got, "[#doubled #growing]")
+		return
+	}
+	// the expected activity is decayed the same way:
+	for i, want := range []float64{48, 24} {
+		want *= tw.avgWeight()
+		if 0.01 < math.Abs(got[i].Delta-want)/want {
+			t.Errorf("THashList.Trending() Delta = %v, want %v", got[i].Delta, want)
+		}
+	}
+} // TestTHashList_TrendingDecay()
+
+/* _EoF_ */